@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusengine"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	EpochFlag = cli.UintFlag{
+		Name:     "epoch",
+		Usage:    "Epoch to be exported",
+		Required: true,
+	}
+	OutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Output DAG file path (\"-\" for stdout)",
+		Value: "-",
+	}
+	DagPathFlag = cli.StringFlag{
+		Name:     "dag",
+		Usage:    "DAG file path written by the export command",
+		Required: true,
+	}
+	MinimizedOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Output path of the minimized DAG file",
+	}
+	ASCIIMaxFlag = cli.IntFlag{
+		Name:  "ascii.max",
+		Usage: "Print the minimized DAG as an ASCII scheme if it has at most this number of events",
+		Value: 200,
+	}
+)
+
+var exportCommand = &cli.Command{
+	Name:   "export",
+	Usage:  "Export an epoch of the event DB into a portable DAG file",
+	Flags:  []cli.Flag{&DbPathFlag, &EpochFlag, &OutFlag},
+	Action: export,
+}
+
+var replayCommand = &cli.Command{
+	Name:   "replay",
+	Usage:  "Recalculate an epoch exported into a DAG file",
+	Flags:  []cli.Flag{&DagPathFlag},
+	Action: replay,
+}
+
+var minimizeCommand = &cli.Command{
+	Name:   "minimize",
+	Usage:  "Shrink a diverging epoch exported into a DAG file to a small subgraph which still diverges",
+	Flags:  []cli.Flag{&DagPathFlag, &MinimizedOutFlag, &ASCIIMaxFlag},
+	Action: minimize,
+}
+
+func export(ctx *cli.Context) error {
+	conn, err := openDB(ctx.String(DbPathFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	epoch := consensus.Epoch(ctx.Uint(EpochFlag.Name))
+	dag, err := consensusengine.ExportEpoch(conn, epoch)
+	if err != nil {
+		return err
+	}
+	if len(dag.Events) == 0 {
+		return fmt.Errorf("no events of epoch %d in the event DB", epoch)
+	}
+
+	path := ctx.String(OutFlag.Name)
+	if path == "-" {
+		return dag.Write(os.Stdout)
+	}
+	return writeDAG(dag, path)
+}
+
+func replay(ctx *cli.Context) error {
+	dag, err := readDAG(ctx.String(DagPathFlag.Name))
+	if err != nil {
+		return err
+	}
+	r := newReport([]*consensusengine.EpochCheckResult{consensusengine.ReplayEpoch(dag)})
+	fmt.Print(r.summary())
+	return r.Result[0].Err()
+}
+
+func minimize(ctx *cli.Context) error {
+	dag, err := readDAG(ctx.String(DagPathFlag.Name))
+	if err != nil {
+		return err
+	}
+	minimized, result, err := consensusengine.MinimizeEpoch(dag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("minimized from %d to %d events\n", len(dag.Events), len(minimized.Events))
+	fmt.Print(newReport([]*consensusengine.EpochCheckResult{result}).summary())
+	if path := ctx.String(MinimizedOutFlag.Name); path != "" {
+		if err := writeDAG(minimized, path); err != nil {
+			return err
+		}
+	}
+	if len(minimized.Events) > ctx.Int(ASCIIMaxFlag.Name) {
+		return nil
+	}
+	scheme, err := minimized.ASCIIscheme()
+	if err != nil {
+		return err
+	}
+	fmt.Print(scheme)
+	return nil
+}
+
+func readDAG(path string) (*consensusengine.EpochDAG, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return consensusengine.ReadEpochDAG(f)
+}
+
+func writeDAG(dag *consensusengine.EpochDAG, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := dag.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
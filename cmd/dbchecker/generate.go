@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/0xsoniclabs/consensus/consensus/consensusengine"
+	"github.com/urfave/cli/v2"
+)
+
+var defaultEventDBConfig = consensusengine.DefaultEventDBConfig()
+
+var (
+	GenOutFlag = cli.StringFlag{
+		Name:     "out",
+		Usage:    "Path of the generated sqlite3 event db, the file must not exist",
+		Required: true,
+	}
+	GenSeedFlag = cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed of the generation, the same flags generate the same event db",
+	}
+	GenEpochsFlag = cli.IntFlag{
+		Name:  "epochs",
+		Usage: "Number of epochs",
+		Value: defaultEventDBConfig.Epochs,
+	}
+	GenValidatorsFlag = cli.IntFlag{
+		Name:  "validators",
+		Usage: "Number of validators in each epoch",
+		Value: defaultEventDBConfig.Validators,
+	}
+	GenCheatersFlag = cli.IntFlag{
+		Name:  "cheaters",
+		Usage: "Number of validators creating forks in each epoch",
+		Value: defaultEventDBConfig.Cheaters,
+	}
+	GenForksFlag = cli.IntFlag{
+		Name:  "forks",
+		Usage: "Max number of forks of a cheater in an epoch",
+		Value: defaultEventDBConfig.Forks,
+	}
+	GenParentsFlag = cli.IntFlag{
+		Name:  "parents",
+		Usage: "Max number of parents of an event",
+		Value: defaultEventDBConfig.Parents,
+	}
+	GenBlocksFlag = cli.IntFlag{
+		Name:  "blocks",
+		Usage: "Number of blocks after which an epoch is sealed",
+		Value: defaultEventDBConfig.EpochBlocks,
+	}
+	GenEventsFlag = cli.IntFlag{
+		Name:  "events",
+		Usage: "Max number of events of a validator in an epoch",
+		Value: defaultEventDBConfig.EpochEvents,
+	}
+)
+
+var generateCommand = &cli.Command{
+	Name:  "generate",
+	Usage: "Generate an event db of random epochs with validator changes and forks",
+	Flags: []cli.Flag{
+		&GenOutFlag, &GenSeedFlag, &GenEpochsFlag, &GenValidatorsFlag, &GenCheatersFlag,
+		&GenForksFlag, &GenParentsFlag, &GenBlocksFlag, &GenEventsFlag,
+	},
+	Action: generate,
+}
+
+func generate(ctx *cli.Context) error {
+	path := ctx.String(GenOutFlag.Name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("event db %s already exists", path)
+	}
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	config := consensusengine.EventDBConfig{
+		Seed:        ctx.Int64(GenSeedFlag.Name),
+		Epochs:      ctx.Int(GenEpochsFlag.Name),
+		Validators:  ctx.Int(GenValidatorsFlag.Name),
+		Cheaters:    ctx.Int(GenCheatersFlag.Name),
+		Forks:       ctx.Int(GenForksFlag.Name),
+		Parents:     ctx.Int(GenParentsFlag.Name),
+		EpochBlocks: ctx.Int(GenBlocksFlag.Name),
+		EpochEvents: ctx.Int(GenEventsFlag.Name),
+	}
+	if err := consensusengine.GenerateEventDB(conn, config); err != nil {
+		conn.Close()
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
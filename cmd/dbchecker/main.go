@@ -22,10 +22,10 @@ import (
 )
 
 var (
+	// DbPathFlag is required by the commands which read the event DB
 	DbPathFlag = cli.StringFlag{
-		Name:     "db",
-		Usage:    "sqlite3 event db path",
-		Required: true,
+		Name:  "db",
+		Usage: "sqlite3 event db path",
 	}
 	EpochMinFlag = cli.UintFlag{
 		Name:  "epoch.min",
@@ -35,6 +35,23 @@ var (
 		Name:  "epoch.max",
 		Usage: "Upper bound (inclusive) for epochs to be checked",
 	}
+	KeepGoingFlag = cli.BoolFlag{
+		Name:  "keep-going",
+		Usage: "Check all the epochs despite the failures and print a per-epoch summary",
+	}
+	ReportFlag = cli.StringFlag{
+		Name:  "report",
+		Usage: "Write a JSON report of the per-epoch results into the file (\"-\" for stdout), implies --keep-going",
+	}
+	WorkersFlag = cli.UintFlag{
+		Name:  "workers",
+		Usage: "Number of epochs checked concurrently, each over a separate DB connection",
+		Value: 1,
+	}
+	ContinuousFlag = cli.BoolFlag{
+		Name:  "continuous",
+		Usage: "Check the epochs by a single engine, sealing each epoch at its last atropos with the validators of the next epoch",
+	}
 )
 
 func main() {
@@ -42,8 +59,9 @@ func main() {
 		Name:        "Event DB Checker",
 		Description: "Consensus regression testing tool",
 		Copyright:   "(c) 2025 Sonic Labs",
-		Flags:       []cli.Flag{&DbPathFlag, &EpochMinFlag, &EpochMaxFlag},
+		Flags:       []cli.Flag{&DbPathFlag, &EpochMinFlag, &EpochMaxFlag, &KeepGoingFlag, &ReportFlag, &WorkersFlag, &ContinuousFlag},
 		Action:      run,
+		Commands:    []*cli.Command{exportCommand, replayCommand, minimizeCommand, generateCommand},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -53,14 +71,15 @@ func main() {
 }
 
 func run(ctx *cli.Context) error {
-	conn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", ctx.String(DbPathFlag.Name)))
+	workers := ctx.Uint(WorkersFlag.Name)
+	if workers == 0 {
+		return fmt.Errorf("invalid number of workers: %d", workers)
+	}
+	conn, err := openDB(ctx.String(DbPathFlag.Name))
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	if err := conn.Ping(); err != nil {
-		return err
-	}
 
 	epochMin, epochMax, err := consensusengine.GetEpochRange(conn)
 	if err != nil {
@@ -76,10 +95,41 @@ func run(ctx *cli.Context) error {
 		return fmt.Errorf("invalid range of epochs requested: [%d, %d]", epochMin, epochMax)
 	}
 
-	for epoch := epochMin; epoch <= epochMax; epoch++ {
-		if err := consensusengine.CheckEpochAgainstDB(conn, epoch); err != nil {
+	keepGoing := ctx.Bool(KeepGoingFlag.Name) || ctx.IsSet(ReportFlag.Name)
+	var results []*consensusengine.EpochCheckResult
+	if ctx.Bool(ContinuousFlag.Name) {
+		if workers != 1 {
+			return fmt.Errorf("--%s can't be combined with --%s", ContinuousFlag.Name, WorkersFlag.Name)
+		}
+		// the epochs following a failure can't be checked by the same engine, regardless of --keep-going
+		results = consensusengine.CheckEpochsContinuously(conn, epochMin, epochMax)
+	} else {
+		conns := []*sql.DB{conn}
+		for uint(len(conns)) < min(workers, uint(epochMax-epochMin+1)) {
+			conn, err := openDB(ctx.String(DbPathFlag.Name))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			conns = append(conns, conn)
+		}
+		results = checkEpochs(conns, epochMin, epochMax, !keepGoing)
+	}
+	if !keepGoing {
+		return results[len(results)-1].Err()
+	}
+
+	r := newReport(results)
+	if path := ctx.String(ReportFlag.Name); path != "" {
+		if err := r.writeJSON(path); err != nil {
 			return err
 		}
 	}
+	if ctx.String(ReportFlag.Name) != "-" {
+		fmt.Print(r.summary())
+	}
+	if r.Failed != 0 {
+		return fmt.Errorf("%d of %d epochs diverged from the event DB", r.Failed, r.Epochs)
+	}
 	return nil
 }
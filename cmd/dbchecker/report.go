@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/consensus/consensus/consensusengine"
+	"github.com/0xsoniclabs/consensus/utils/textcolumns"
+)
+
+// report is the machine-readable result of a run
+type report struct {
+	Epochs int                                 `json:"epochs"`
+	Failed int                                 `json:"failed"`
+	Result []*consensusengine.EpochCheckResult `json:"result"`
+}
+
+func newReport(results []*consensusengine.EpochCheckResult) *report {
+	r := &report{
+		Epochs: len(results),
+		Result: results,
+	}
+	for _, result := range results {
+		if result.Divergence != nil {
+			r.Failed++
+		}
+	}
+	return r
+}
+
+// summary formats the per-epoch results as a table
+func (r *report) summary() string {
+	columns := [][]string{
+		{"epoch"}, {"events"}, {"roots"}, {"atropoi"}, {"recalculated"}, {"status"},
+	}
+	for _, result := range r.Result {
+		status := "ok"
+		if d := result.Divergence; d != nil {
+			status = fmt.Sprintf("%s #%d: %s", d.Kind, d.Position, d.Message)
+		}
+		columns[0] = append(columns[0], fmt.Sprint(result.Epoch))
+		columns[1] = append(columns[1], fmt.Sprint(result.Events))
+		columns[2] = append(columns[2], fmt.Sprint(result.Roots))
+		columns[3] = append(columns[3], fmt.Sprint(result.ExpectedAtropoi))
+		columns[4] = append(columns[4], fmt.Sprint(result.RecalculatedAtropoi))
+		columns[5] = append(columns[5], status)
+	}
+	texts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		texts = append(texts, strings.Join(column, "\n"))
+	}
+	// the last row of TextColumns is blank
+	table := strings.TrimRight(textcolumns.TextColumns(texts...), " \t\n") + "\n"
+	if skipped := r.skipped(); len(skipped) != 0 {
+		table += fmt.Sprintf("checks skipped due to missing tables: %s\n", strings.Join(skipped, ", "))
+	}
+	return table + fmt.Sprintf("%d of %d epochs failed\n", r.Failed, r.Epochs)
+}
+
+// skipped returns the optional tables which are missing for any of the epochs
+func (r *report) skipped() []string {
+	var skipped []string
+	for _, result := range r.Result {
+		for _, table := range result.Skipped {
+			if !slices.Contains(skipped, table) {
+				skipped = append(skipped, table)
+			}
+		}
+	}
+	return skipped
+}
+
+// writeJSON writes the report into the file, or into stdout if path is "-"
+func (r *report) writeJSON(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
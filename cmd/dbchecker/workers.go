@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusengine"
+)
+
+// openDB opens a read-only connection to the event DB
+func openDB(path string) (*sql.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("Required flag %q not set", DbPathFlag.Name)
+	}
+	conn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, err
+	}
+	// a worker uses a single connection at a time
+	conn.SetMaxOpenConns(1)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// checkEpochs checks the epochs in [epochMin, epochMax] concurrently, each worker over a separate DB connection.
+// The results are ordered by epoch. If stopOnFailure is set, the results end with the first failed epoch,
+// which is the same regardless of the number of workers.
+func checkEpochs(conns []*sql.DB, epochMin, epochMax consensus.Epoch, stopOnFailure bool) []*consensusengine.EpochCheckResult {
+	results := make([]*consensusengine.EpochCheckResult, epochMax-epochMin+1)
+	// firstFailed is the index of the first failed epoch found so far, the later epochs are skipped
+	var firstFailed atomic.Int64
+	firstFailed.Store(int64(len(results)))
+
+	epochs := make(chan int)
+	go func() {
+		defer close(epochs)
+		for i := range results {
+			if stopOnFailure && int64(i) > firstFailed.Load() {
+				return
+			}
+			epochs <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *sql.DB) {
+			defer wg.Done()
+			for i := range epochs {
+				if stopOnFailure && int64(i) > firstFailed.Load() {
+					continue
+				}
+				results[i] = consensusengine.CheckEpoch(conn, epochMin+consensus.Epoch(i))
+				if results[i].Err() != nil {
+					lowerTo(&firstFailed, int64(i))
+				}
+			}
+		}(conn)
+	}
+	wg.Wait()
+
+	if stopOnFailure && firstFailed.Load() < int64(len(results)) {
+		results = results[:firstFailed.Load()+1]
+	}
+	return results
+}
+
+// lowerTo atomically sets the value to v if v is lower
+func lowerTo(value *atomic.Int64, v int64) {
+	for old := value.Load(); v < old; old = value.Load() {
+		if value.CompareAndSwap(old, v) {
+			return
+		}
+	}
+}
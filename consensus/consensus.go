@@ -10,6 +10,8 @@
 
 package consensus
 
+// Errors wrapping ErrIntegrity, returned by any of the methods, mean that consensus state
+// can no longer be trusted and processing must be stopped.
 type Consensus interface {
 	// Process takes event for processing.
 	Process(e Event) error
@@ -25,7 +27,8 @@ type EndBlockFn func() (sealEpoch *Validators)
 type BlockCallbacks struct {
 	// ApplyEvent is called on confirmation of each event during block processing.
 	// Cannot be called twice for the same event.
-	// The order in which ApplyBlock is called for events is deterministic but undefined. It's application's responsibility to sort events according to its needs.
+	// The order in which ApplyBlock is called for events is deterministic but undefined, unless the consensus engine is configured with an order.
+	// Otherwise, it's application's responsibility to sort events according to its needs.
 	// It's application's responsibility to interpret this data (e.g. events may be related to batches of transactions or other ordered data).
 	ApplyEvent ApplyEventFn
 	// EndBlock indicates that ApplyEvent was called for all the events
@@ -12,11 +12,19 @@ package consensusengine
 
 import (
 	"database/sql"
+	"os"
+	"path/filepath"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
 )
 
+// testEpochDB is a small event DB of a single epoch, used by the conformance tester
+const testEpochDB = "../../cmd/conf_tester/test/resources/test-epoch-76.db"
+
 func TestRegressionData_FantomNetwork(t *testing.T) {
 	testRegressionData(t, "testdata/events-5577.db")
 }
@@ -51,6 +59,222 @@ func testRegressionData(t *testing.T, dbPath string) {
 	}
 }
 
+func TestCheckEpoch(t *testing.T) {
+	assertar := assert.New(t)
+
+	conn := openTestEpochDB(t)
+	result := CheckEpoch(conn, 76)
+	assertar.NoError(result.Err())
+	assertar.Nil(result.Divergence)
+	assertar.Equal(7422, result.Events)
+	assertar.Equal(416, result.ExpectedAtropoi)
+	assertar.Equal(416, result.RecalculatedAtropoi)
+	assertar.Positive(result.Roots)
+	assertar.Equal([]string{CheatersTable, ConfirmedTable, RootsTable}, result.Skipped)
+
+	// an epoch without validators and events
+	result = CheckEpoch(conn, 1)
+	assertar.NoError(result.Err())
+	assertar.Zero(result.Events)
+}
+
+func TestCheckEpoch_Divergence(t *testing.T) {
+	t.Run("frame", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openTestEpochDB(t)
+		_, err := conn.Exec(`
+			UPDATE Event SET FrameId = FrameId + 1
+			WHERE EventId = (SELECT EventId FROM Event ORDER BY LamportNumber DESC LIMIT 1)
+		`)
+		assertar.NoError(err)
+
+		result := CheckEpoch(conn, 76)
+		assertar.Error(result.Err())
+		if assertar.NotNil(result.Divergence) {
+			assertar.Equal(DivergenceEvent, result.Divergence.Kind)
+			assertar.Equal(result.Events-1, result.Divergence.Position)
+			assertar.Contains(result.Divergence.Message, "incorrect frame")
+		}
+		// atropoi decided before the divergent event match
+		assertar.Positive(result.RecalculatedAtropoi)
+	})
+
+	t.Run("atropoi", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openTestEpochDB(t)
+		_, err := conn.Exec(`INSERT INTO Atropos SELECT MAX(EventId) FROM Event`)
+		assertar.NoError(err)
+
+		result := CheckEpoch(conn, 76)
+		assertar.Error(result.Err())
+		assertar.Equal(result.Err(), CheckEpochAgainstDB(conn, 76))
+		if assertar.NotNil(result.Divergence) {
+			assertar.Equal(DivergenceAtropos, result.Divergence.Kind)
+			assertar.Equal(416, result.Divergence.Position)
+		}
+		assertar.Equal(417, result.ExpectedAtropoi)
+	})
+}
+
+func TestCheckEpoch_Blocks(t *testing.T) {
+	divergences := map[string]struct {
+		mutation string
+		kind     string
+		position int
+	}{
+		"cheaters": {
+			mutation: `INSERT INTO AtroposCheater SELECT MIN(AtroposId), 1 FROM Atropos`,
+			kind:     DivergenceCheaters,
+			position: 0,
+		},
+		"confirmed": {
+			mutation: `
+				DELETE FROM AtroposEvent
+				WHERE AtroposId = (SELECT AtroposId FROM Atropos ORDER BY AtroposId LIMIT 1 OFFSET 1) AND EventId != AtroposId`,
+			kind:     DivergenceConfirmed,
+			position: 1,
+		},
+		"roots": {
+			mutation: `
+				DELETE FROM Root
+				WHERE EventId = (SELECT MIN(r.EventId) FROM Root r JOIN Event e ON r.EventId = e.EventId WHERE e.FrameId = 3)`,
+			kind:     DivergenceRoots,
+			position: 3,
+		},
+	}
+
+	t.Run("match", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openTestEpochDB(t)
+		writeExpectedBlocks(t, conn, 76)
+
+		result := CheckEpoch(conn, 76)
+		assertar.NoError(result.Err())
+		assertar.Empty(result.Skipped)
+	})
+
+	t.Run("missing table", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openTestEpochDB(t)
+		writeExpectedBlocks(t, conn, 76)
+		_, err := conn.Exec(divergences["roots"].mutation)
+		assertar.NoError(err)
+		_, err = conn.Exec(`DROP TABLE Root`)
+		assertar.NoError(err)
+
+		result := CheckEpoch(conn, 76)
+		assertar.NoError(result.Err())
+		assertar.Equal([]string{RootsTable}, result.Skipped)
+	})
+
+	for name, divergence := range divergences {
+		t.Run(name, func(t *testing.T) {
+			assertar := assert.New(t)
+			conn := openTestEpochDB(t)
+			writeExpectedBlocks(t, conn, 76)
+			_, err := conn.Exec(divergence.mutation)
+			assertar.NoError(err)
+
+			result := CheckEpoch(conn, 76)
+			assertar.Error(result.Err())
+			if assertar.NotNil(result.Divergence) {
+				assertar.Equal(divergence.kind, result.Divergence.Kind)
+				assertar.Equal(divergence.position, result.Divergence.Position)
+			}
+		})
+	}
+}
+
+// writeExpectedBlocks creates the optional tables of the event DB and fills them with the recalculated epoch
+func writeExpectedBlocks(t *testing.T, conn *sql.DB, epoch consensus.Epoch) {
+	t.Helper()
+	testLachesis, eventStore, _, orderedEvents, err := setupElection(conn, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := executeElection(testLachesis, eventStore, orderedEvents); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[consensus.EventHash]int64{}
+	rows, err := conn.Query(`SELECT EventId, EventHash FROM Event WHERE EpochId = ?`, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id int64
+		var hashStr string
+		if err := rows.Scan(&id, &hashStr); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := decodeHashStr(hashStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[hash] = id
+	}
+	rows.Close()
+
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := func(query string, args ...any) {
+		if _, err := tx.Exec(query, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exec(`CREATE TABLE AtroposCheater (AtroposId INTEGER NOT NULL, ValidatorId INTEGER NOT NULL, PRIMARY KEY (AtroposId, ValidatorId))`)
+	exec(`CREATE TABLE AtroposEvent (AtroposId INTEGER NOT NULL, EventId INTEGER NOT NULL, PRIMARY KEY (AtroposId, EventId))`)
+	exec(`CREATE TABLE Root (EventId INTEGER NOT NULL, PRIMARY KEY (EventId))`)
+	for _, block := range testLachesis.blocks {
+		for _, cheater := range block.Cheaters {
+			exec(`INSERT INTO AtroposCheater VALUES (?, ?)`, ids[block.Atropos], cheater)
+		}
+		confirmed, err := testLachesis.store.GetEventsConfirmedBy(block.Atropos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range confirmed {
+			exec(`INSERT INTO AtroposEvent VALUES (?, ?)`, ids[block.Atropos], ids[e])
+		}
+	}
+	for frame := consensus.FirstFrame; ; frame++ {
+		frameRoots, err := testLachesis.store.GetFrameRoots(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(frameRoots) == 0 {
+			break
+		}
+		for _, root := range frameRoots {
+			exec(`INSERT INTO Root VALUES (?)`, ids[root.RootHash])
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// openTestEpochDB opens a writable copy of testEpochDB
+func openTestEpochDB(t *testing.T) *sql.DB {
+	t.Helper()
+	data, err := os.ReadFile(testEpochDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "events.db")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
 func benchmarkElection(b *testing.B, dbPath string) {
 	conn, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
@@ -22,16 +22,16 @@ import (
 
 func TestAtroposHeap_RandomPushPop(t *testing.T) {
 	atroposHeap := NewAtroposHeap()
-	atropoi := make([]*atroposDecision, 100)
+	atropoi := make([]*AtroposDecision, 100)
 	for i := range atropoi {
-		atropoi[i] = &atroposDecision{AtroposHash: consensus.EventHash{byte(i)}, Frame: consensus.Frame(i)}
+		atropoi[i] = &AtroposDecision{AtroposHash: consensus.EventHash{byte(i)}, Frame: consensus.Frame(i)}
 	}
 	rand.Shuffle(len(atropoi), func(i, j int) { atropoi[i], atropoi[j] = atropoi[j], atropoi[i] })
 	for _, atroposDecision := range atropoi {
 		heap.Push(atroposHeap, atroposDecision)
 	}
 	for i := range atropoi {
-		want, got := consensus.EventHash{byte(i)}, heap.Pop(atroposHeap).(*atroposDecision).AtroposHash
+		want, got := consensus.EventHash{byte(i)}, heap.Pop(atroposHeap).(*AtroposDecision).AtroposHash
 		if want != got {
 			t.Errorf("expected popped atropos hash to be %v, got: %v", want, got)
 		}
@@ -42,50 +42,50 @@ func TestAtroposHeap_SingleDeliveredSequence(t *testing.T) {
 	testAtroposHeapDelivery(
 		t,
 		100,
-		[]*atroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
-		[]*atroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
-		[]*atroposDecision{},
+		[]*AtroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
+		[]*AtroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
+		[]*AtroposDecision{},
 	)
 }
 func TestAtroposHeap_EmptyDeliverySequence(t *testing.T) {
 	testAtroposHeapDelivery(
 		t,
 		100,
-		[]*atroposDecision{{101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
-		[]*atroposDecision{},
-		[]*atroposDecision{{101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
+		[]*AtroposDecision{{101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
+		[]*AtroposDecision{},
+		[]*AtroposDecision{{101, consensus.EventHash{101}}, {102, consensus.EventHash{102}}},
 	)
 }
 func TestAtroposHeap_BrokenDeliverySequence(t *testing.T) {
 	testAtroposHeapDelivery(
 		t,
 		100,
-		[]*atroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {104, consensus.EventHash{104}}, {105, consensus.EventHash{105}}},
-		[]*atroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}},
-		[]*atroposDecision{{104, consensus.EventHash{104}}, {105, consensus.EventHash{105}}},
+		[]*AtroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}, {104, consensus.EventHash{104}}, {105, consensus.EventHash{105}}},
+		[]*AtroposDecision{{100, consensus.EventHash{100}}, {101, consensus.EventHash{101}}},
+		[]*AtroposDecision{{104, consensus.EventHash{104}}, {105, consensus.EventHash{105}}},
 	)
 }
 
 func testAtroposHeapDelivery(
 	t *testing.T,
 	frameToDeliver consensus.Frame,
-	atropoi []*atroposDecision,
-	expectedDelivered []*atroposDecision,
-	expectedContainer []*atroposDecision,
+	atropoi []*AtroposDecision,
+	expectedDelivered []*AtroposDecision,
+	expectedContainer []*AtroposDecision,
 ) {
 	atroposHeap := NewAtroposHeap()
 	for _, atropos := range atropoi {
 		heap.Push(atroposHeap, atropos)
 	}
 	delivered := atroposHeap.getDeliveryReadyAtropoi(frameToDeliver)
-	if !slices.EqualFunc(delivered, expectedDelivered, func(a, b *atroposDecision) bool { return a.AtroposHash == b.AtroposHash }) {
+	if !slices.EqualFunc(delivered, expectedDelivered, func(a, b *AtroposDecision) bool { return a.AtroposHash == b.AtroposHash }) {
 		t.Errorf("incorrect delivered atropi sequence, expected: %v, got: %v", expectedDelivered, delivered)
 	}
-	if !slices.EqualFunc(atroposHeap.container, expectedContainer, func(a, b *atroposDecision) bool { return a.AtroposHash == b.AtroposHash }) {
+	if !slices.EqualFunc(atroposHeap.container, expectedContainer, func(a, b *AtroposDecision) bool { return a.AtroposHash == b.AtroposHash }) {
 		t.Errorf("incorrect remaining atropi container, expected: %v, got: %v", expectedContainer, atroposHeap.container)
 	}
 }
 
-func (ad *atroposDecision) String() string {
+func (ad *AtroposDecision) String() string {
 	return fmt.Sprintf("[frame: %d, hash: %v]", ad.Frame, ad.AtroposHash)
 }
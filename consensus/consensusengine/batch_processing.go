@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+var (
+	ErrParentsOrder   = errors.New("event is in the batch before its parent")
+	ErrDuplicateEvent = errors.New("event is in the batch twice")
+)
+
+// BatchError is returned by ProcessBatch when an event of the batch is rejected.
+// Events before Index are processed, the rejected event and the events after it are not.
+type BatchError struct {
+	Index int
+	Event consensus.EventHash
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch event #%d %s: %v", e.Index, e.Event.String(), e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ProcessBatch takes a batch of events into processing.
+// Events must be ordered by parents, as for Process, and must be available in the EventSource.
+// The DAG index is flushed once per batch, unlike Process which flushes after every event.
+// If an event is rejected, e.g. with consensus.ErrUnknownParent if its parent is neither in the batch nor processed,
+// the preceding events remain processed and a *BatchError is returned.
+// ProcessBatch is not safe for concurrent use.
+func (p *IndexedLachesis) ProcessBatch(events consensus.Events) error {
+	positions := make(map[consensus.EventHash]int, len(events))
+	for i, e := range events {
+		if _, ok := positions[e.ID()]; !ok {
+			positions[e.ID()] = i
+		}
+	}
+
+	defer p.DagIndexer.DropNotFlushed()
+	// start is the first event which isn't flushed into the DAG index yet
+	start := 0
+	for i, e := range events {
+		err := p.checkBatchEvent(events, positions, i)
+		if err == nil {
+			err = p.DagIndexer.Add(e)
+		}
+		if err == nil {
+			epoch := p.store.GetEpoch()
+			err = p.Lachesis.Process(e)
+			if err == nil && p.store.GetEpoch() != epoch {
+				// epoch is sealed, DAG index is reset
+				start = i + 1
+			}
+		}
+		if err != nil {
+			if errors.Is(err, consensus.ErrIntegrity) {
+				return p.fail(err)
+			}
+			return p.rejectBatchEvent(events[start:i], i, e, err)
+		}
+	}
+	return p.fail(p.DagIndexer.Flush())
+}
+
+// checkBatchEvent checks the event's position in the batch, and that its parents outside the batch are known
+func (p *IndexedLachesis) checkBatchEvent(events consensus.Events, positions map[consensus.EventHash]int, i int) error {
+	e := events[i]
+	if positions[e.ID()] != i {
+		return ErrDuplicateEvent
+	}
+	for _, parent := range e.Parents() {
+		pos, ok := positions[parent]
+		if ok && pos > i {
+			return ErrParentsOrder
+		}
+		if !ok && p.Input.GetEvent(parent) == nil {
+			return errors.Wrapf(consensus.ErrUnknownParent, "parent %s", parent.String())
+		}
+	}
+	if e.Epoch() != p.store.GetEpoch() {
+		return ErrWrongEpoch
+	}
+	return nil
+}
+
+// rejectBatchEvent drops the DAG index of the rejected event and flushes the index of the processed events
+func (p *IndexedLachesis) rejectBatchEvent(processed consensus.Events, i int, e consensus.Event, err error) error {
+	p.DagIndexer.DropNotFlushed()
+	for _, e := range processed {
+		if err := p.DagIndexer.Add(e); err != nil {
+			return p.fail(err)
+		}
+	}
+	if err := p.DagIndexer.Flush(); err != nil {
+		return p.fail(err)
+	}
+	return &BatchError{Index: i, Event: e.ID(), Err: err}
+}
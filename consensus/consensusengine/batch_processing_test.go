@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestProcessBatch(t *testing.T) {
+	assertar := assert.New(t)
+
+	weights := []consensus.Weight{1, 2, 3, 4}
+	nodes := consensustest.GenNodes(len(weights))
+	lchs := make([]*CoreLachesis, 0, 2)
+	inputs := make([]*consensustest.TestEventSource, 0, 2)
+	for i := 0; i < 2; i++ {
+		lch, _, input, _ := NewCoreLachesis(nodes, weights)
+		lchs = append(lchs, lch)
+		inputs = append(inputs, input)
+	}
+	const epochs = 3
+	maxEpochBlocks := TestMaxEpochEvents / 20
+	for _, _lch := range lchs {
+		lch := _lch // capture
+		lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+			if lch.store.GetLastDecidedFrame()+1 == consensus.Frame(maxEpochBlocks) {
+				return lch.store.GetValidators()
+			}
+			return nil
+		}
+	}
+
+	ordered := map[consensus.Epoch]consensus.Events{}
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	for epoch := consensus.Epoch(1); epoch <= epochs; epoch++ {
+		consensustest.ForEachRandFork(nodes, nodes[:1], TestMaxEpochEvents, len(nodes), 10, r, consensustest.ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				ordered[epoch] = append(ordered[epoch], e)
+				inputs[0].SetEvent(e)
+				assertar.NoError(lchs[0].Process(e))
+			},
+			Build: func(e consensus.MutableEvent, name string) error {
+				if epoch != lchs[0].store.GetEpoch() {
+					return errors.New("epoch already sealed, skip")
+				}
+				e.SetEpoch(epoch)
+				return lchs[0].Build(e)
+			},
+		})
+	}
+
+	// process the same events in batches of random size
+	for epoch := consensus.Epoch(1); epoch <= epochs; epoch++ {
+		ee := reorder(ordered[epoch])
+		for _, e := range ee {
+			inputs[1].SetEvent(e)
+		}
+		for len(ee) > 0 && lchs[1].store.GetEpoch() == epoch {
+			size := min(1+r.Intn(50), len(ee))
+			err := lchs[1].ProcessBatch(ee[:size])
+			var batchErr *BatchError
+			if errors.As(err, &batchErr) {
+				// the rest of the batch is after the epoch sealing
+				assertar.ErrorIs(err, ErrWrongEpoch)
+				assertar.Equal(epoch+1, lchs[1].store.GetEpoch())
+			} else {
+				assertar.NoError(err)
+			}
+			ee = ee[size:]
+		}
+		assertar.Equal(epoch+1, lchs[1].store.GetEpoch())
+	}
+
+	compareResults(t, lchs)
+}
+
+func TestProcessBatch_RejectedEvent(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(4)
+	expected, _, expectedInput, _ := NewCoreLachesis(nodes, nil)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+
+	var ordered consensus.Events
+	r := rand.New(rand.NewSource(2)) // nolint:gosec
+	consensustest.ForEachRandEvent(nodes, 100, len(nodes), r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			ordered = append(ordered, e)
+			expectedInput.SetEvent(e)
+			input.SetEvent(e)
+			assertar.NoError(expected.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(1)
+			return expected.Build(e)
+		},
+	})
+
+	// put a child before its parent
+	const bad = 50
+	child := bad + 1
+	for ; child < len(ordered); child++ {
+		if ordered[child].SelfParent() != nil && *ordered[child].SelfParent() == ordered[bad].ID() {
+			break
+		}
+	}
+	if !assertar.Less(child, len(ordered)) {
+		return
+	}
+	batch := make(consensus.Events, 0, len(ordered))
+	batch = append(batch, ordered[:bad]...)
+	batch = append(batch, ordered[child])
+	batch = append(batch, ordered[bad:child]...)
+	batch = append(batch, ordered[child+1:]...)
+
+	err := lch.ProcessBatch(batch)
+	var batchErr *BatchError
+	if !assertar.ErrorAs(err, &batchErr) {
+		return
+	}
+	assertar.ErrorIs(err, ErrParentsOrder)
+	assertar.Equal(bad, batchErr.Index)
+	assertar.Equal(ordered[child].ID(), batchErr.Event)
+
+	// the processed events must remain consistent, so that the rest can be processed
+	assertar.NoError(lch.ProcessBatch(ordered[bad:]))
+	assertar.Equal(*expected.store.GetLastDecidedState(), *lch.store.GetLastDecidedState())
+	assertar.NotEmpty(expected.blocks)
+	assertar.Equal(expected.blocks, lch.blocks)
+}
+
+func TestProcessBatch_UnknownParent(t *testing.T) {
+	nodes := consensustest.GenNodes(4)
+	var ordered consensus.Events
+	expected, _, expectedInput, _ := NewCoreLachesis(nodes, nil)
+	r := rand.New(rand.NewSource(3)) // nolint:gosec
+	consensustest.ForEachRandEvent(nodes, 100, len(nodes), r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			ordered = append(ordered, e)
+			expectedInput.SetEvent(e)
+			assert.NoError(t, expected.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(1)
+			return expected.Build(e)
+		},
+	})
+
+	const bad = 50
+	// the parent of the orphan is unknown
+	orphan := &consensustest.TestEvent{}
+	orphan.SetEpoch(1)
+	orphan.SetCreator(nodes[0])
+	orphan.SetSeq(1)
+	orphan.SetLamport(1)
+	orphan.SetParents(consensus.EventHashes{consensustest.FakeEventHash()})
+	orphan.Name = "orphan"
+	orphan.SetID(consensustest.CalcHashForTestEvent(orphan))
+	// the parent of the child is known, but it's neither processed nor in the batch
+	child := ordered[bad+1]
+	for _, e := range ordered[bad+1:] {
+		if e.SelfParent() != nil && *e.SelfParent() == ordered[bad].ID() {
+			child = e
+			break
+		}
+	}
+
+	for name, rejected := range map[string]consensus.Event{"orphan": orphan, "unprocessed parent": child} {
+		t.Run(name, func(t *testing.T) {
+			assertar := assert.New(t)
+
+			lch, _, input, _ := NewCoreLachesis(nodes, nil)
+			for _, e := range ordered {
+				input.SetEvent(e)
+			}
+			input.SetEvent(orphan)
+
+			batch := append(append(consensus.Events{}, ordered[:bad]...), rejected)
+			err := lch.ProcessBatch(batch)
+			var batchErr *BatchError
+			if !assertar.ErrorAs(err, &batchErr) {
+				return
+			}
+			assertar.ErrorIs(err, consensus.ErrUnknownParent)
+			assertar.NotErrorIs(err, consensus.ErrIntegrity)
+			assertar.Equal(bad, batchErr.Index)
+			assertar.Equal(rejected.ID(), batchErr.Event)
+
+			// the processed events are flushed, so that the rest of the batch can be processed
+			assertar.NoError(lch.ProcessBatch(ordered[bad:]))
+			assertar.NotEmpty(expected.blocks)
+			assertar.Equal(expected.blocks, lch.blocks)
+		})
+	}
+}
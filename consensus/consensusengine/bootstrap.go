@@ -25,6 +25,21 @@ func (p *Orderer) Bootstrap(callback OrdererCallbacks) error {
 	// block handler must be set before p.handleElection
 	p.callback = callback
 
+	if err := p.config.Validate(); err != nil {
+		return err
+	}
+	if err := p.store.SetQuorumThreshold(p.config.QuorumThreshold); err != nil {
+		return err
+	}
+
+	// restore persistent states, so that the store getters can't fail afterwards
+	if _, err := p.store.LoadEpochState(); err != nil {
+		return p.fail(err)
+	}
+	if _, err := p.store.LoadLastDecidedState(); err != nil {
+		return p.fail(err)
+	}
+
 	// restore current epoch DB
 	err := p.loadEpochDB()
 	if err != nil {
@@ -33,11 +48,18 @@ func (p *Orderer) Bootstrap(callback OrdererCallbacks) error {
 	if p.callback.EpochDBLoaded != nil {
 		p.callback.EpochDBLoaded(p.store.GetEpoch())
 	}
-	p.election = NewElection(p.store.GetLastDecidedFrame()+1, p.store.GetValidators(), p.dagIndex.ForklessCause, p.store.GetFrameRoots)
+	if p.config.NewElection != nil {
+		p.election = p.config.NewElection(p.store.GetLastDecidedFrame()+1, p.store.GetValidators(), p.dagIndex.ForklessCause, p.store.GetFrameRoots)
+	} else {
+		el := NewElection(p.store.GetLastDecidedFrame()+1, p.store.GetValidators(), p.dagIndex.ForklessCause, p.store.GetFrameRoots)
+		if p.config.ElectionTracer != nil {
+			el.tracer = p.traceElection
+		}
+		p.election = el
+	}
 
 	// events reprocessing
-	err = p.bootstrapElection()
-	return err
+	return p.fail(p.bootstrapElection())
 }
 
 // Reset switches epoch state to a new empty epoch.
@@ -45,6 +67,7 @@ func (p *Orderer) Reset(epoch consensus.Epoch, validators *consensus.Validators)
 	if err := p.store.SwitchGenesis(&consensusstore.Genesis{Epoch: epoch, Validators: validators}); err != nil {
 		return err
 	}
+	p.metrics.onEpochSwitched()
 	// reset internal epoch DB
 	err := p.resetEpochStore(epoch)
 	if err != nil {
@@ -53,7 +76,7 @@ func (p *Orderer) Reset(epoch consensus.Epoch, validators *consensus.Validators)
 	if p.callback.EpochDBLoaded != nil {
 		p.callback.EpochDBLoaded(p.store.GetEpoch())
 	}
-	p.election.ResetEpoch(consensus.FirstFrame, validators)
+	p.election.ResetEpoch(consensus.FirstFrame, p.store.GetValidators())
 	return nil
 }
 
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestByzantine_Equivocation(t *testing.T) {
+	nodes := consensustest.GenNodes(7)
+	testByzantine(t, nodes, nil, consensustest.ByzantineConfig{
+		Adversaries: map[consensus.ValidatorID]consensustest.Adversary{
+			nodes[0]: {Forks: 10},
+			nodes[1]: {Forks: 3},
+		},
+	})
+}
+
+func TestByzantine_Withholding(t *testing.T) {
+	nodes := consensustest.GenNodes(7)
+	testByzantine(t, nodes, nil, consensustest.ByzantineConfig{
+		Adversaries: map[consensus.ValidatorID]consensustest.Adversary{
+			nodes[0]: {Withhold: 50},
+			nodes[1]: {Withhold: 20, Forks: 5},
+		},
+	})
+}
+
+func TestByzantine_SubsetPeers(t *testing.T) {
+	nodes := consensustest.GenNodes(7)
+	testByzantine(t, nodes, nil, consensustest.ByzantineConfig{
+		Adversaries: map[consensus.ValidatorID]consensustest.Adversary{
+			nodes[0]: {Peers: nodes[1:2]},
+			nodes[1]: {Peers: nodes[:2]},
+		},
+	})
+}
+
+func TestByzantine_Partition(t *testing.T) {
+	nodes := consensustest.GenNodes(7)
+	weights := []consensus.Weight{5, 4, 3, 3, 2, 2, 1}
+	validators := make(consensus.ValidatorsBuilder, len(nodes))
+	for i, v := range nodes {
+		validators[v] = weights[i]
+	}
+	for _, share := range []consensus.Weight{5, 10, 14} {
+		testByzantine(t, nodes, weights, consensustest.ByzantineConfig{
+			Partitions: []consensustest.Partition{{
+				From:  100,
+				To:    400,
+				Group: consensustest.WeightedGroup(validators.Build(), share),
+			}},
+		})
+	}
+}
+
+func TestByzantine_Combined(t *testing.T) {
+	nodes := consensustest.GenNodes(10)
+	validators := make(consensus.ValidatorsBuilder, len(nodes))
+	for _, v := range nodes {
+		validators[v] = 1
+	}
+	testByzantine(t, nodes, nil, consensustest.ByzantineConfig{
+		Adversaries: map[consensus.ValidatorID]consensustest.Adversary{
+			nodes[0]: {Forks: 5, Withhold: 10},
+			nodes[1]: {Forks: 5, Peers: nodes[:3]},
+			nodes[2]: {Withhold: 30, Peers: nodes[2:5]},
+		},
+		Partitions: []consensustest.Partition{
+			{From: 50, To: 150, Group: consensustest.WeightedGroup(validators.Build(), 3)},
+			{From: 300, To: 350, Group: nodes[5:]},
+		},
+	})
+}
+
+// testByzantine runs a node per validator on its own view of the DAG, and checks that the honest nodes
+// decide the same blocks
+func testByzantine(t *testing.T, nodes []consensus.ValidatorID, weights []consensus.Weight, config consensustest.ByzantineConfig) {
+	t.Helper()
+	for seed := int64(0); seed < 3; seed++ {
+		assertar := assert.New(t)
+
+		config.Steps = 100 * len(nodes)
+		config.Parents = len(nodes)/2 + 1
+		config.MaxDelay = 10
+
+		lchs := map[consensus.ValidatorID]*CoreLachesis{}
+		inputs := map[consensus.ValidatorID]*consensustest.TestEventSource{}
+		for _, v := range nodes {
+			lchs[v], _, inputs[v], _ = NewCoreLachesis(nodes, weights)
+		}
+
+		r := rand.New(rand.NewSource(seed)) // nolint:gosec
+		consensustest.ForEachByzantineEvent(nodes, config, r, consensustest.ForEachByzantine{
+			Build: func(creator consensus.ValidatorID, e consensus.MutableEvent, name string) error {
+				e.SetEpoch(consensus.FirstEpoch)
+				return lchs[creator].Build(e)
+			},
+			Deliver: func(node consensus.ValidatorID, e consensus.Event, name string) {
+				inputs[node].SetEvent(e)
+				assertar.NoError(lchs[node].Process(e), "node %d, event %s", node, name)
+			},
+		})
+		if t.Failed() {
+			return
+		}
+
+		// all the events are delivered after the partitions heal, so the honest nodes observe the same DAG
+		var expected *CoreLachesis
+		for _, v := range nodes {
+			if !config.Adversaries[v].Honest() {
+				continue
+			}
+			if expected == nil {
+				expected = lchs[v]
+				assertar.NotEmpty(expected.blocks, "seed %d", seed)
+				continue
+			}
+			assertar.Equal(expected.blocks, lchs[v].blocks, "seed %d, node %d", seed, v)
+		}
+	}
+}
@@ -11,6 +11,7 @@
 package consensusengine
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/0xsoniclabs/consensus/consensus"
@@ -40,6 +41,29 @@ func testCalcFrameIdx(t *testing.T, gap int) {
 	}
 }
 
+func TestProcess_MissingParentIsReported(t *testing.T) {
+	nodes := consensustest.GenNodes(2)
+	lch, _, store, _ := NewCoreLachesis(nodes, nil)
+
+	genesis := processTestEvent(t, lch, store, nodes[0], 1, consensus.EventHashes{})
+	unknown := consensustest.FakeEventHash()
+
+	event := &consensustest.TestEvent{}
+	event.SetSeq(2)
+	event.SetCreator(nodes[0])
+	event.SetParents(consensus.EventHashes{genesis.ID(), unknown})
+	event.SetLamport(genesis.Lamport() + 1)
+	event.SetEpoch(lch.store.GetEpoch())
+	event.SetID([24]byte{1})
+	store.SetEvent(event)
+
+	if err := lch.Process(event); !errors.Is(err, consensus.ErrUnknownParent) || errors.Is(err, consensus.ErrIntegrity) {
+		t.Fatalf("expected unknown parent error, got: %v", err)
+	}
+	// state is intact, the next event is processed normally
+	processTestEvent(t, lch, store, nodes[1], 1, consensus.EventHashes{})
+}
+
 var maxLamport consensus.Lamport = 0
 
 // processTestEvent builds and pipes the event through main Lacehsis' DAG manipulation pipeline
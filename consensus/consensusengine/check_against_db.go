@@ -11,9 +11,12 @@
 package consensusengine
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"slices"
+	"sort"
 
 	"github.com/0xsoniclabs/consensus/consensus"
 	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
@@ -42,8 +45,10 @@ func setupElection(conn *sql.DB, epoch consensus.Epoch) (*CoreLachesis, *consens
 		return nil, nil, nil, nil, nil
 	}
 
-	testLachesis, _, eventStore, _ := NewCoreLachesis(validators, weights)
-	testLachesis.store.SwitchGenesis(&consensusstore.Genesis{Epoch: epoch, Validators: testLachesis.store.GetValidators()})
+	testLachesis, eventStore, err := newEpochLachesis(epoch, validators, weights)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	eventsOrdered, eventMap, err := getEvents(conn, epoch)
 	if err != nil {
@@ -53,6 +58,15 @@ func setupElection(conn *sql.DB, epoch consensus.Epoch) (*CoreLachesis, *consens
 	return testLachesis, eventStore, eventMap, eventsOrdered, nil
 }
 
+// newEpochLachesis creates a CoreLachesis which starts from the epoch with the validators
+func newEpochLachesis(epoch consensus.Epoch, validators []consensus.ValidatorID, weights []consensus.Weight) (*CoreLachesis, *consensustest.TestEventSource, error) {
+	testLachesis, _, eventStore, _ := NewCoreLachesis(validators, weights)
+	if err := testLachesis.store.SwitchGenesis(&consensusstore.Genesis{Epoch: epoch, Validators: testLachesis.store.GetValidators()}); err != nil {
+		return nil, nil, err
+	}
+	return testLachesis, eventStore, nil
+}
+
 func executeElection(testLachesis *CoreLachesis, eventStore *consensustest.TestEventSource, eventsOrdered []*dbEvent) error {
 	for _, event := range eventsOrdered {
 		if err := ingestEvent(testLachesis, eventStore, event); err != nil {
@@ -63,36 +77,250 @@ func executeElection(testLachesis *CoreLachesis, eventStore *consensustest.TestE
 	return nil
 }
 
+// Kinds of the divergence from the event DB.
+const (
+	DivergenceError     = "error"
+	DivergenceEvent     = "event"
+	DivergenceAtropos   = "atropos"
+	DivergenceCheaters  = "cheaters"
+	DivergenceConfirmed = "confirmed"
+	DivergenceRoots     = "roots"
+)
+
+// Optional tables of the event DB, the checks are skipped if a table is missing:
+//
+//	AtroposCheater(AtroposId, ValidatorId) - cheaters of the block decided by the Atropos
+//	AtroposEvent(AtroposId, EventId) - events confirmed under the Atropos, including the Atropos itself
+//	Root(EventId) - roots of the frame of the event
+const (
+	CheatersTable  = "AtroposCheater"
+	ConfirmedTable = "AtroposEvent"
+	RootsTable     = "Root"
+)
+
+// Divergence is the first point where the recalculated consensus diverges from the event DB.
+type Divergence struct {
+	Kind string `json:"kind"`
+	// Position is the index of the event in the Lamport order for DivergenceEvent,
+	// the index of the Atropos in the epoch for DivergenceAtropos, DivergenceCheaters and DivergenceConfirmed,
+	// or the frame for DivergenceRoots
+	Position int    `json:"position"`
+	Message  string `json:"message"`
+}
+
+// EpochCheckResult is the result of an epoch check against the event DB.
+type EpochCheckResult struct {
+	Epoch               consensus.Epoch `json:"epoch"`
+	Events              int             `json:"events"`
+	Roots               int             `json:"roots"`
+	ExpectedAtropoi     int             `json:"expectedAtropoi"`
+	RecalculatedAtropoi int             `json:"recalculatedAtropoi"`
+	// Skipped are the optional tables missing in the event DB, whose checks are skipped
+	Skipped []string `json:"skipped,omitempty"`
+	// Unprocessed is the number of events following the sealing of the epoch, see CheckEpochsContinuously
+	Unprocessed int `json:"unprocessed,omitempty"`
+	// Divergence is nil if the epoch matches the event DB
+	Divergence *Divergence `json:"divergence,omitempty"`
+
+	err error
+}
+
+// Err returns the error of the first divergence, or nil if the epoch matches the event DB.
+func (r *EpochCheckResult) Err() error {
+	return r.err
+}
+
+func (r *EpochCheckResult) diverge(kind string, position int, err error) *EpochCheckResult {
+	r.Divergence = &Divergence{Kind: kind, Position: position, Message: err.Error()}
+	r.err = err
+	return r
+}
+
+// CheckEpochAgainstDB recalculates the epoch and returns the error of the first divergence from the event DB.
 func CheckEpochAgainstDB(conn *sql.DB, epoch consensus.Epoch) error {
+	return CheckEpoch(conn, epoch).Err()
+}
+
+// CheckEpoch recalculates the epoch and compares it with the event DB.
+// Unlike CheckEpochAgainstDB, it collects the statistics of the epoch along with the first divergence.
+func CheckEpoch(conn *sql.DB, epoch consensus.Epoch) *EpochCheckResult {
+	result := &EpochCheckResult{Epoch: epoch}
 	testLachesis, eventStore, eventMap, orderedEvents, err := setupElection(conn, epoch)
 	if err != nil {
-		return err
+		return result.diverge(DivergenceError, 0, err)
 	}
+	expectedAtropoi, err := getAtropoi(conn, epoch)
+	if err != nil {
+		return result.diverge(DivergenceError, 0, err)
+	}
+	expected, err := getExpectedBlocks(conn, epoch)
+	if err != nil {
+		return result.diverge(DivergenceError, 0, err)
+	}
+	return checkEpoch(result, testLachesis, eventStore, eventMap, orderedEvents, expectedAtropoi, expected)
+}
 
-	recalculatedAtropoi := make([]consensus.EventHash, 0)
-	// Capture the elected atropoi by planting the `applyBlock` callback (nil by default)
+// checkEpoch recalculates the loaded epoch and compares it with the expectations.
+// testLachesis is nil if the epoch has no validators.
+func checkEpoch(result *EpochCheckResult, testLachesis *CoreLachesis, eventStore *consensustest.TestEventSource, eventMap map[consensus.EventHash]*dbEvent, orderedEvents []*dbEvent, expectedAtropoi []consensus.EventHash, expected *expectedBlocks) *EpochCheckResult {
+	epoch := result.Epoch
+	result.Events = len(orderedEvents)
+	result.ExpectedAtropoi = len(expectedAtropoi)
+	result.Skipped = expected.skipped
+	if testLachesis == nil {
+		// no validators
+		return result
+	}
+
+	recalculatedBlocks := make([]*consensus.Block, 0)
+	// Capture the decided blocks by planting the `applyBlock` callback (nil by default)
 	testLachesis.applyBlock = func(block *consensus.Block) *consensus.Validators {
-		recalculatedAtropoi = append(recalculatedAtropoi, block.Atropos)
+		recalculatedBlocks = append(recalculatedBlocks, block)
 		return nil
 	}
 
-	if err := executeElection(testLachesis, eventStore, orderedEvents); err != nil {
-		return err
+	var err error
+	failedEvent := len(orderedEvents)
+	for i, event := range orderedEvents {
+		if err = ingestEvent(testLachesis, eventStore, event); err != nil {
+			failedEvent = i
+			break
+		}
 	}
+	result.RecalculatedAtropoi = len(recalculatedBlocks)
+	roots, rootsErr := countRoots(testLachesis)
+	if rootsErr != nil {
+		return result.diverge(DivergenceError, 0, rootsErr)
+	}
+	result.Roots = roots
 
-	expectedAtropoi, err := getAtropoi(conn, epoch)
+	// the recalculated blocks are decided before the failed event
+	for idx, block := range recalculatedBlocks {
+		if idx >= len(expectedAtropoi) {
+			break
+		}
+		if kind, err := checkBlock(testLachesis, epoch, idx, block, expectedAtropoi[idx], expected, eventMap); err != nil {
+			return result.diverge(kind, idx, err)
+		}
+	}
 	if err != nil {
-		return err
+		return result.diverge(DivergenceEvent, failedEvent, err)
 	}
-	if want, got := len(expectedAtropoi), len(recalculatedAtropoi); want > got {
-		return fmt.Errorf("incorrect number of atropoi recalculated for epoch %d, expected at least: %d, got: %d", epoch, want, got)
+	if expected.roots != nil {
+		if frame, err := checkRoots(testLachesis, epoch, eventMap, expected.roots); err != nil {
+			return result.diverge(DivergenceRoots, int(frame), err)
+		}
 	}
-	for idx := range expectedAtropoi {
-		if want, got := expectedAtropoi[idx], recalculatedAtropoi[idx]; want != got {
-			return fmt.Errorf("incorrect atropos for epoch %d on position %d, expected: %s got: %s", epoch, idx, eventMap[want].String(), eventMap[got].String())
+	if want, got := len(expectedAtropoi), len(recalculatedBlocks); want > got {
+		return result.diverge(DivergenceAtropos, got, fmt.Errorf("incorrect number of atropoi recalculated for epoch %d, expected at least: %d, got: %d", epoch, want, got))
+	}
+	return result
+}
+
+// checkBlock compares the recalculated block on the position idx with the expected Atropos and the optional expectations,
+// returns the kind of the divergence along with its error. The confirmed events are read from the store of the current epoch.
+func checkBlock(testLachesis *CoreLachesis, epoch consensus.Epoch, idx int, block *consensus.Block, want consensus.EventHash, expected *expectedBlocks, eventMap map[consensus.EventHash]*dbEvent) (string, error) {
+	if got := block.Atropos; want != got {
+		return DivergenceAtropos, fmt.Errorf("incorrect atropos for epoch %d on position %d, expected: %s got: %s", epoch, idx, eventMap[want].String(), eventMap[got].String())
+	}
+	if expected.cheaters != nil {
+		if want, got := sortedCheaters(expected.cheaters[block.Atropos]), sortedCheaters(block.Cheaters); !slices.Equal(want, got) {
+			return DivergenceCheaters, fmt.Errorf("incorrect cheaters for epoch %d on position %d, expected: %v got: %v", epoch, idx, want, got)
 		}
 	}
-	return nil
+	if expected.confirmed != nil {
+		confirmed, err := testLachesis.store.GetEventsConfirmedBy(block.Atropos)
+		if err != nil {
+			return DivergenceError, err
+		}
+		if missing, extra := diffEvents(expected.confirmed[block.Atropos], confirmed.Set()); len(missing) != 0 || len(extra) != 0 {
+			return DivergenceConfirmed, fmt.Errorf("incorrect events confirmed for epoch %d on position %d, expected: %d got: %d, %s",
+				epoch, idx, len(expected.confirmed[block.Atropos]), len(confirmed), describeDiff(eventMap, missing, extra))
+		}
+	}
+	return "", nil
+}
+
+// checkRoots compares the roots stored by AddRoot with the expected roots, frame by frame,
+// and returns the first frame which differs
+func checkRoots(testLachesis *CoreLachesis, epoch consensus.Epoch, eventMap map[consensus.EventHash]*dbEvent, expected map[consensus.Frame]consensus.EventHashSet) (consensus.Frame, error) {
+	lastFrame := consensus.Frame(0)
+	for frame := range expected {
+		lastFrame = max(lastFrame, frame)
+	}
+	for frame := consensus.FirstFrame; ; frame++ {
+		frameRoots, err := testLachesis.store.GetFrameRoots(frame)
+		if err != nil {
+			return frame, err
+		}
+		if len(frameRoots) == 0 && frame > lastFrame {
+			return 0, nil
+		}
+		got := make(consensus.EventHashSet, len(frameRoots))
+		for _, root := range frameRoots {
+			got.Add(root.RootHash)
+		}
+		if missing, extra := diffEvents(expected[frame], got); len(missing) != 0 || len(extra) != 0 {
+			return frame, fmt.Errorf("incorrect roots for epoch %d in frame %d, expected: %d got: %d, %s",
+				epoch, frame, len(expected[frame]), len(got), describeDiff(eventMap, missing, extra))
+		}
+	}
+}
+
+// sortedCheaters returns a copy of the cheaters sorted by ID, as the cheaters are compared regardless of the order
+func sortedCheaters(cheaters consensus.Cheaters) consensus.Cheaters {
+	sorted := append(make(consensus.Cheaters, 0, len(cheaters)), cheaters...)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// diffEvents returns the sorted events which are expected but not recalculated (missing), and vice versa (extra)
+func diffEvents(want, got consensus.EventHashSet) (missing, extra consensus.EventHashes) {
+	for e := range want {
+		if !got.Contains(e) {
+			missing = append(missing, e)
+		}
+	}
+	for e := range got {
+		if !want.Contains(e) {
+			extra = append(extra, e)
+		}
+	}
+	for _, events := range []consensus.EventHashes{missing, extra} {
+		sort.Slice(events, func(i, j int) bool {
+			return bytes.Compare(events[i].Bytes(), events[j].Bytes()) < 0
+		})
+	}
+	return missing, extra
+}
+
+// describeDiff describes the first missing and the first extra event
+func describeDiff(eventMap map[consensus.EventHash]*dbEvent, missing, extra consensus.EventHashes) string {
+	describe := func(events consensus.EventHashes) string {
+		if len(events) == 0 {
+			return "none"
+		}
+		if e, ok := eventMap[events[0]]; ok {
+			return fmt.Sprintf("%d, first: %s", len(events), e.String())
+		}
+		return fmt.Sprintf("%d, first: %s", len(events), events[0].String())
+	}
+	return fmt.Sprintf("missing: %s, extra: %s", describe(missing), describe(extra))
+}
+
+// countRoots returns the number of roots in the frames of the current epoch
+func countRoots(testLachesis *CoreLachesis) (int, error) {
+	roots := 0
+	for frame := consensus.FirstFrame; ; frame++ {
+		frameRoots, err := testLachesis.store.GetFrameRoots(frame)
+		if err != nil {
+			return roots, err
+		}
+		if len(frameRoots) == 0 {
+			return roots, nil
+		}
+		roots += len(frameRoots)
+	}
 }
 
 func GetEpochRange(conn *sql.DB) (consensus.Epoch, consensus.Epoch, error) {
@@ -143,9 +371,14 @@ func processLocalEvent(testLachesis *CoreLachesis, event *consensustest.TestEven
 	if targetFrame != event.Frame() {
 		return fmt.Errorf("incorrect frame recalculated for event: [validator: %d, seq: %d], expected: %d, got: %d", event.Creator(), event.Seq(), targetFrame, event.Frame())
 	}
-	selfParentFrame := testLachesis.getSelfParentFrame(event)
+	selfParentFrame, err := testLachesis.getSelfParentFrame(event)
+	if err != nil {
+		return fmt.Errorf("error wihile processing event: [validator: %d, seq: %d], err: %w", event.Creator(), event.Seq(), err)
+	}
 	if selfParentFrame != event.Frame() {
-		testLachesis.store.AddRoot(event)
+		if err := testLachesis.store.AddRoot(event); err != nil {
+			return fmt.Errorf("error wihile processing event: [validator: %d, seq: %d], err: %w", event.Creator(), event.Seq(), err)
+		}
 		if err := testLachesis.handleElection(event); err != nil {
 			return fmt.Errorf("error wihile processing event: [validator: %d, seq: %d], err: %v", event.Creator(), event.Seq(), err)
 		}
@@ -307,6 +540,147 @@ func getAtropoi(conn *sql.DB, epoch consensus.Epoch) ([]consensus.EventHash, err
 	return atropoi, nil
 }
 
+// expectedBlocks are the expectations of the optional tables, a nil map means the table is missing
+type expectedBlocks struct {
+	cheaters  map[consensus.EventHash]consensus.Cheaters
+	confirmed map[consensus.EventHash]consensus.EventHashSet
+	roots     map[consensus.Frame]consensus.EventHashSet
+	skipped   []string
+}
+
+func getExpectedBlocks(conn *sql.DB, epoch consensus.Epoch) (*expectedBlocks, error) {
+	expected := &expectedBlocks{}
+	for _, table := range []string{CheatersTable, ConfirmedTable, RootsTable} {
+		exists, err := hasTable(conn, table)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			expected.skipped = append(expected.skipped, table)
+			continue
+		}
+		switch table {
+		case CheatersTable:
+			expected.cheaters, err = getCheaters(conn, epoch)
+		case ConfirmedTable:
+			expected.confirmed, err = getConfirmedEvents(conn, epoch)
+		case RootsTable:
+			expected.roots, err = getRoots(conn, epoch)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expected, nil
+}
+
+func hasTable(conn *sql.DB, table string) (bool, error) {
+	var count int
+	err := conn.QueryRow(`
+		SELECT COUNT(*)
+		FROM sqlite_master
+		WHERE type = 'table' AND name = ?
+	`, table).Scan(&count)
+	return count != 0, err
+}
+
+func getCheaters(conn *sql.DB, epoch consensus.Epoch) (map[consensus.EventHash]consensus.Cheaters, error) {
+	rows, err := conn.Query(`
+		SELECT a.EventHash, c.ValidatorId
+		FROM AtroposCheater c JOIN Event a ON c.AtroposId = a.EventId
+		WHERE a.EpochId = ?
+	`, epoch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cheaters := make(map[consensus.EventHash]consensus.Cheaters)
+	for rows.Next() {
+		var atroposHashStr string
+		var validatorId consensus.ValidatorID
+		err = rows.Scan(&atroposHashStr, &validatorId)
+		if err != nil {
+			return nil, err
+		}
+
+		atroposHash, err := decodeHashStr(atroposHashStr)
+		if err != nil {
+			return nil, err
+		}
+		cheaters[atroposHash] = append(cheaters[atroposHash], validatorId)
+	}
+	return cheaters, rows.Err()
+}
+
+func getConfirmedEvents(conn *sql.DB, epoch consensus.Epoch) (map[consensus.EventHash]consensus.EventHashSet, error) {
+	rows, err := conn.Query(`
+		SELECT a.EventHash, e.EventHash
+		FROM AtroposEvent c JOIN Event a ON c.AtroposId = a.EventId JOIN Event e ON c.EventId = e.EventId
+		WHERE a.EpochId = ?
+	`, epoch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	confirmed := make(map[consensus.EventHash]consensus.EventHashSet)
+	for rows.Next() {
+		var atroposHashStr string
+		var eventHashStr string
+		err = rows.Scan(&atroposHashStr, &eventHashStr)
+		if err != nil {
+			return nil, err
+		}
+
+		atroposHash, err := decodeHashStr(atroposHashStr)
+		if err != nil {
+			return nil, err
+		}
+		eventHash, err := decodeHashStr(eventHashStr)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed[atroposHash] == nil {
+			confirmed[atroposHash] = consensus.EventHashSet{}
+		}
+		confirmed[atroposHash].Add(eventHash)
+	}
+	return confirmed, rows.Err()
+}
+
+func getRoots(conn *sql.DB, epoch consensus.Epoch) (map[consensus.Frame]consensus.EventHashSet, error) {
+	rows, err := conn.Query(`
+		SELECT e.EventHash, e.FrameId
+		FROM Root r JOIN Event e ON r.EventId = e.EventId
+		WHERE e.EpochId = ?
+	`, epoch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roots := make(map[consensus.Frame]consensus.EventHashSet)
+	for rows.Next() {
+		var rootHashStr string
+		var frame consensus.Frame
+		err = rows.Scan(&rootHashStr, &frame)
+		if err != nil {
+			return nil, err
+		}
+
+		rootHash, err := decodeHashStr(rootHashStr)
+		if err != nil {
+			return nil, err
+		}
+		if roots[frame] == nil {
+			roots[frame] = consensus.EventHashSet{}
+		}
+		roots[frame].Add(rootHash)
+	}
+	return roots, rows.Err()
+}
+
 // hashStr is in hex format, i.e. 0x1a2b3c4d...
 func decodeHashStr(hashStr string) (consensus.EventHash, error) {
 	hashSlice, err := hex.DecodeString(hashStr[2:])
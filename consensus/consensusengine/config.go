@@ -10,9 +10,51 @@
 
 package consensusengine
 
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/utils/metrics"
+)
+
 type Config struct {
 	// Suppresses the frame missmatch panic - used only for importing older historical event files, disabled by default
 	SuppressFramePanic bool
+	// ConfirmedEventsOrder, if set, defines the order in which the confirmed events of a block are applied.
+	// Parents are always applied before their children regardless of the order.
+	// If nil, events are applied in the undefined (but deterministic) order of the DAG traversal.
+	ConfirmedEventsOrder EventLessFn
+	// BlockLog enables the persistent log of the decided blocks, see consensusstore.BlockRecord. It's disabled by default.
+	BlockLog bool
+	// BlockLogEpochs is the number of the latest sealed epochs kept in the block log, 0 keeps all the epochs
+	BlockLogEpochs consensus.Epoch
+	// QuorumThreshold is the fault-tolerance threshold applied to validators of every epoch.
+	// It must be the same on all the nodes. The zero value stands for the default 2/3 threshold.
+	QuorumThreshold consensus.QuorumThreshold
+	// Metrics is the optional registry of the consensus metrics
+	Metrics metrics.Registry
+	// ElectionTracer, if set, receives a trace of every root vote, e.g. ElectionTraceRecorder.Trace.
+	// It's supported only by the default election, i.e. it can't be combined with NewElection.
+	ElectionTracer ElectionTracer
+	// NewElection, if set, creates the Election instead of the default NewElection, e.g. NewRecursiveElection.
+	// It must be the same on all the nodes.
+	NewElection NewElectionFn
+}
+
+// ErrElectionTracerUnsupported is returned by Config.Validate if ElectionTracer is set along with NewElection.
+var ErrElectionTracerUnsupported = errors.New("election tracer is supported only by the default election")
+
+// Validate checks that the config is safe to use, it's called on Bootstrap.
+func (c Config) Validate() error {
+	if err := c.QuorumThreshold.Validate(); err != nil {
+		return fmt.Errorf("invalid consensus config: %w", err)
+	}
+	if c.ElectionTracer != nil && c.NewElection != nil {
+		return fmt.Errorf("invalid consensus config: %w", ErrElectionTracerUnsupported)
+	}
+	return nil
 }
 
 // DefaultConfig for livenet.
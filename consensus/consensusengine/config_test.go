@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+	"github.com/0xsoniclabs/consensus/utils/adapters"
+	"github.com/0xsoniclabs/consensus/vecengine"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assertar := assert.New(t)
+
+	assertar.NoError(LiteConfig().Validate())
+	assertar.NoError(Config{QuorumThreshold: consensus.QuorumThreshold{Numerator: 3, Denominator: 4}}.Validate())
+	assertar.ErrorIs(Config{QuorumThreshold: consensus.QuorumThreshold{Numerator: 1, Denominator: 2}}.Validate(), consensus.ErrUnsafeQuorumThreshold)
+	assertar.ErrorIs(Config{QuorumThreshold: consensus.QuorumThreshold{Numerator: 1, Denominator: 0}}.Validate(), consensus.ErrUnsafeQuorumThreshold)
+
+	// the tracer would be silently dropped by a custom election
+	recorder := &ElectionTraceRecorder{}
+	assertar.NoError(Config{ElectionTracer: recorder.Trace}.Validate())
+	assertar.NoError(Config{NewElection: NewRecursiveElection}.Validate())
+	assertar.ErrorIs(Config{ElectionTracer: recorder.Trace, NewElection: NewRecursiveElection}.Validate(), ErrElectionTracerUnsupported)
+}
+
+func TestBootstrap_UnsafeQuorumThreshold(t *testing.T) {
+	assertar := assert.New(t)
+
+	store := consensusstore.NewMemStore()
+	assertar.NoError(store.ApplyGenesis(&consensusstore.Genesis{
+		Validators: consensus.EqualWeightValidators(consensustest.GenNodes(3), 1),
+		Epoch:      consensus.FirstEpoch,
+	}))
+	config := LiteConfig()
+	config.QuorumThreshold = consensus.QuorumThreshold{Numerator: 3, Denominator: 5}
+	dagIndexer := &adapters.VectorToDagIndexer{Engine: vecengine.NewIndex(nil, vecengine.LiteConfig(), vecengine.GetEngineCallbacks)}
+	lch := NewIndexedLachesis(store, consensustest.NewTestEventSource(), dagIndexer, nil, config)
+	assertar.ErrorIs(lch.Bootstrap(consensus.ConsensusCallbacks{}), consensus.ErrUnsafeQuorumThreshold)
+}
+
+func TestLachesis_QuorumThreshold(t *testing.T) {
+	assertar := assert.New(t)
+
+	weights := []consensus.Weight{1, 2, 3, 4, 5}
+	nodes := consensustest.GenNodes(len(weights))
+	config := LiteConfig()
+	config.QuorumThreshold = consensus.QuorumThreshold{Numerator: 4, Denominator: 5}
+
+	lastFrames := map[bool]consensus.Frame{}
+	for _, custom := range []bool{false, true} {
+		cfg := LiteConfig()
+		if custom {
+			cfg = config
+		}
+		lchs := make([]*CoreLachesis, 0, 2)
+		inputs := make([]*consensustest.TestEventSource, 0, 2)
+		for i := 0; i < 2; i++ {
+			lch, _, input, _ := newCoreLachesis(nodes, weights, cfg)
+			lchs = append(lchs, lch)
+			inputs = append(inputs, input)
+		}
+		if custom {
+			assertar.Equal(consensus.Weight(13), lchs[0].store.GetValidators().Quorum())
+		} else {
+			assertar.Equal(consensus.Weight(11), lchs[0].store.GetValidators().Quorum())
+		}
+
+		// the same DAG for both the thresholds, only the frames are different
+		var ordered consensus.Events
+		r := rand.New(rand.NewSource(1)) // nolint:gosec
+		consensustest.ForEachRandEvent(nodes, TestMaxEpochEvents, len(nodes), r, consensustest.ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				ordered = append(ordered, e)
+				inputs[0].SetEvent(e)
+				assertar.NoError(lchs[0].Process(e))
+			},
+			Build: func(e consensus.MutableEvent, name string) error {
+				e.SetEpoch(consensus.FirstEpoch)
+				return lchs[0].Build(e)
+			},
+		})
+		for _, e := range reorder(ordered) {
+			inputs[1].SetEvent(e)
+		}
+		for _, e := range ordered {
+			assertar.NoError(lchs[1].Process(e))
+		}
+
+		assertar.NotEmpty(lchs[0].blocks)
+		assertar.Equal(lchs[0].blocks, lchs[1].blocks)
+		lastFrames[custom] = lchs[0].store.GetLastDecidedFrame()
+	}
+	// a greater threshold requires more events per frame
+	assertar.Less(lastFrames[true], lastFrames[false])
+}
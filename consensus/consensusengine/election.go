@@ -11,25 +11,63 @@
 package consensusengine
 
 import (
+	"bytes"
 	"container/heap"
+	"sort"
 
 	"github.com/0xsoniclabs/consensus/consensus"
 	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
 )
 
 type (
-	ForklessCauseFn func(a consensus.EventHash, b consensus.EventHash) bool
-	GetFrameRootsFn func(f consensus.Frame) []consensusstore.RootDescriptor
+	ForklessCauseFn func(a consensus.EventHash, b consensus.EventHash) (bool, error)
+	GetFrameRootsFn func(f consensus.Frame) ([]consensusstore.RootDescriptor, error)
 )
 
-type atroposDecision struct {
+// AtroposDecision is the Atropos of a decided frame.
+type AtroposDecision struct {
 	Frame       consensus.Frame
 	AtroposHash consensus.EventHash
 }
 
+// Election decides the Atropos of every frame by the votes of the roots of the next frames.
+// Every implementation must decide the frames in the same way on all the nodes, regardless of the order
+// in which the roots are voted, as long as the roots of a frame are voted after their forkless caused roots.
+// The default implementation is created by NewElection, another one may be selected by Config.NewElection.
+type Election interface {
+	// VoteAndAggregate votes by the root of the frame, created by the validator, and aggregates the votes of the
+	// roots of the previous frames, which are forkless caused by the root.
+	// Returns the decisions which are ready for the delivery, i.e. of the consecutive frames starting
+	// with the first undelivered frame, in the order of the frames.
+	VoteAndAggregate(frame consensus.Frame, validatorID consensus.ValidatorID, rootHash consensus.EventHash) ([]*AtroposDecision, error)
+	// ResetEpoch discards all the votes, and starts the election of the validators from the frameToDeliver.
+	ResetEpoch(frameToDeliver consensus.Frame, validators *consensus.Validators)
+}
+
+// ElectionCheckpointer is an Election whose vote state is persisted when frames are decided,
+// so that Bootstrap doesn't need to re-vote the roots. The roots of an Election which isn't
+// an ElectionCheckpointer are re-voted from the first undecided frame on Bootstrap.
+type ElectionCheckpointer interface {
+	Election
+	// Checkpoint returns a snapshot of the vote state
+	Checkpoint() (*consensusstore.ElectionState, error)
+	// Restore loads the vote state from the snapshot
+	Restore(state *consensusstore.ElectionState)
+}
+
+// NewElectionFn creates an Election of the validators, starting from the frameToDeliver.
+type NewElectionFn func(
+	frameToDeliver consensus.Frame,
+	validators *consensus.Validators,
+	forklessCauseFn ForklessCauseFn,
+	getFrameRoots GetFrameRootsFn,
+) Election
+
+var _ ElectionCheckpointer = (*election)(nil)
+
 type rootVoteContext struct {
 	frameToDeliverOffset consensus.Frame
-	voteMatrix           []int32
+	voteMatrix           []int64
 }
 
 type election struct {
@@ -41,9 +79,17 @@ type election struct {
 	vote           map[consensus.Frame][]map[consensus.EventHash]*rootVoteContext
 	validatorIDMap map[consensus.ValidatorID]consensus.ValidatorIndex
 	validatorCount consensus.Frame
+	// electionQuorum is the doubled quorum threshold of the total weight, see QuorumThreshold.ElectionQuorum
+	electionQuorum consensus.Weight
 
 	atroposDeliveryBuffer *atroposHeap
 	frameToDeliver        consensus.Frame
+
+	// voteMatrixSize is the size of the vote matrix of the last voted root
+	voteMatrixSize int
+
+	// tracer is optional
+	tracer ElectionTracer
 }
 
 func NewElection(
@@ -68,71 +114,115 @@ func (el *election) ResetEpoch(frameToDeliver consensus.Frame, validators *conse
 	el.vote = make(map[consensus.Frame][]map[consensus.EventHash]*rootVoteContext)
 	el.validatorCount = consensus.Frame(validators.Len())
 	el.validatorIDMap = validators.Idxs()
+	el.electionQuorum = validators.QuorumThreshold().ElectionQuorum(validators.TotalWeight())
 }
 
 func (el *election) VoteAndAggregate(
 	frame consensus.Frame,
 	validatorId consensus.ValidatorID,
 	rootHash consensus.EventHash,
-) ([]*atroposDecision, error) {
+) ([]*AtroposDecision, error) {
 	validatorIdx := el.validatorIDMap[validatorId]
 	el.prepareNewElectorRoot(frame, validatorIdx, rootHash)
 	if frame <= el.frameToDeliver {
-		return []*atroposDecision{}, nil
+		return []*AtroposDecision{}, nil
 	}
 
-	aggregationMatrix := make([]int32, (frame-el.frameToDeliver-1)*el.validatorCount, (frame-el.frameToDeliver)*el.validatorCount)
-	directVoteVector := initInt32WithConst(-1, int(el.validatorCount))
+	aggregationMatrix := make([]int64, (frame-el.frameToDeliver-1)*el.validatorCount, (frame-el.frameToDeliver)*el.validatorCount)
+	directVoteVector := initInt64WithConst(-1, int(el.validatorCount))
 
-	observedRoots := el.observedRoots(rootHash, frame-1)
-	observedRootsWeight := int32(0)
+	observedRoots, err := el.observedRoots(rootHash, frame-1)
+	if err != nil {
+		return nil, err
+	}
+	observedRootsWeight := int64(0)
 
 	for _, observedRoot := range observedRoots {
 		validatorIdx := el.validatorIDMap[observedRoot.ValidatorID]
 		directVoteVector[validatorIdx] = 1
-		observedRootsWeight += int32(el.validators.GetWeightByIdx(validatorIdx))
+		observedRootsWeight += int64(el.validators.GetWeightByIdx(validatorIdx))
 
 		if el.vote[frame-1][validatorIdx] != nil {
 			if rootContext, ok := el.vote[frame-1][validatorIdx][observedRoot.RootHash]; ok {
 				nonDeliveredFramesOffset := (el.frameToDeliver - rootContext.frameToDeliverOffset) * el.validatorCount
-				addInt32Vecs(aggregationMatrix, aggregationMatrix, rootContext.voteMatrix[nonDeliveredFramesOffset:])
+				addInt64Vecs(aggregationMatrix, aggregationMatrix, rootContext.voteMatrix[nonDeliveredFramesOffset:])
 			}
 		}
 	}
 
-	el.decide(frame, aggregationMatrix, observedRootsWeight)
+	var trace *ElectionTrace
+	if el.tracer != nil {
+		trace = el.traceVote(frame, validatorId, rootHash, directVoteVector, observedRootsWeight, aggregationMatrix)
+	}
+
+	if err := el.decide(frame, aggregationMatrix, observedRootsWeight, trace); err != nil {
+		return nil, err
+	}
+	if trace != nil {
+		el.tracer(*trace)
+	}
 
-	normalizeInt32Vec(aggregationMatrix, aggregationMatrix)
+	normalizeInt64Vec(aggregationMatrix, aggregationMatrix)
 	aggregationMatrix = append(aggregationMatrix, directVoteVector...)
 
-	mulInt32VecWithConst(aggregationMatrix, aggregationMatrix, int32(el.validators.GetWeightByIdx(validatorIdx)))
+	mulInt64VecWithConst(aggregationMatrix, aggregationMatrix, int64(el.validators.GetWeightByIdx(validatorIdx)))
 	el.vote[frame][validatorIdx][rootHash].voteMatrix = aggregationMatrix
+	el.voteMatrixSize = len(aggregationMatrix)
 
 	atropoi := el.atroposDeliveryBuffer.getDeliveryReadyAtropoi(el.frameToDeliver)
 	el.frameToDeliver += consensus.Frame(len(atropoi))
 	return atropoi, nil
 }
 
-func (el *election) decide(aggregatingFrame consensus.Frame, aggregationMatr []int32, observedRootsWeight int32) {
-	// Q = ceil((4*TotalValidatorWeight - 3*observedRootsWeight)/3)
-	// numerator (Q_0) can exceed the int32 limits before division
-	Q_0 := 4*int64(el.validators.TotalWeight()) - 3*int64(observedRootsWeight)
-	Q := int32((Q_0 + 3 - 1) / 3)
-	yesDecisions := boolMaskInt32Vec(aggregationMatr, func(x int32) bool { return x >= Q })
-	noDecisions := boolMaskInt32Vec(aggregationMatr, func(x int32) bool { return x <= -Q })
+// decide checks the candidates of the undecided frames, trace is optional
+func (el *election) decide(aggregatingFrame consensus.Frame, aggregationMatr []int64, observedRootsWeight int64, trace *ElectionTrace) error {
+	// Q = ceil(2*threshold*TotalValidatorWeight) - observedRootsWeight, i.e. ceil((4*TotalValidatorWeight - 3*observedRootsWeight)/3) for the 2/3 threshold
+	// it doesn't exceed the int64 limits, as TotalValidatorWeight <= MaxTotalWeight
+	Q := int64(el.electionQuorum) - observedRootsWeight
+	yesDecisions := boolMaskInt64Vec(aggregationMatr, func(x int64) bool { return x >= Q })
+	noDecisions := boolMaskInt64Vec(aggregationMatr, func(x int64) bool { return x <= -Q })
+	if trace != nil {
+		trace.Q = Q
+	}
 
+	// frames are decided independently, they are sorted only to make the traces deterministic
+	frames := make([]consensus.Frame, 0, len(el.vote))
 	for frame := range el.vote {
 		if frame < el.frameToDeliver || frame >= aggregatingFrame-1 {
 			continue
 		}
+		frames = append(frames, frame)
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i] < frames[j] })
 
+	for _, frame := range frames {
 		for _, candidateValidator := range el.validators.SortedIDs() {
 			validatorIdx := el.validatorIDMap[candidateValidator]
 			voteMatrixOffset := (frame-el.frameToDeliver)*el.validatorCount + consensus.Frame(validatorIdx)
 
+			var candidate *CandidateTrace
+			if trace != nil {
+				trace.Candidates = append(trace.Candidates, CandidateTrace{
+					Frame:     frame,
+					Validator: candidateValidator,
+					Votes:     aggregationMatr[voteMatrixOffset],
+					Result:    CandidateUndecided,
+				})
+				candidate = &trace.Candidates[len(trace.Candidates)-1]
+			}
+
 			if yesDecisions[voteMatrixOffset] {
-				atroposHash := el.elect(frame, candidateValidator)
-				heap.Push(el.atroposDeliveryBuffer, &atroposDecision{frame, atroposHash})
+				var electTrace *ElectTrace
+				if candidate != nil {
+					candidate.Result = CandidateDecided
+					electTrace = &ElectTrace{}
+					candidate.Elect = electTrace
+				}
+				atroposHash, err := el.elect(frame, candidateValidator, electTrace)
+				if err != nil {
+					return err
+				}
+				heap.Push(el.atroposDeliveryBuffer, &AtroposDecision{frame, atroposHash})
 				el.cleanupDecidedFrame(frame)
 				break
 			}
@@ -140,47 +230,80 @@ func (el *election) decide(aggregatingFrame consensus.Frame, aggregationMatr []i
 			if !noDecisions[voteMatrixOffset] {
 				break
 			}
+			if candidate != nil {
+				candidate.Result = CandidateRejected
+			}
 		}
 	}
+	return nil
 }
 
 // elect picks the final atropos event once its frame and validator number have been finalized
 // by the "upper frame" root votes'. This is trivial in case of non-forking events as such
 // roots are uniquely identified by (frame, validator).
 // In the case of a fork, a tiebreaker algorithm has to be run.
-func (el *election) elect(frame consensus.Frame, validatorCandidate consensus.ValidatorID) consensus.EventHash {
+// The optional trace records the tiebreak path.
+func (el *election) elect(frame consensus.Frame, validatorCandidate consensus.ValidatorID, trace *ElectTrace) (consensus.EventHash, error) {
 	validatorIdx := el.validatorIDMap[validatorCandidate]
 	candidateMap := el.vote[frame][validatorIdx]
-	atroposHash := consensus.EventHash{}
+	candidates := make(consensus.EventHashes, 0, len(candidateMap))
 	for hash := range candidateMap {
-		atroposHash = hash
+		candidates = append(candidates, hash)
+	}
+	// the result doesn't depend on the order, forks are sorted only to make the tiebreak path deterministic
+	sort.Slice(candidates, func(i, j int) bool { return bytes.Compare(candidates[i].Bytes(), candidates[j].Bytes()) < 0 })
+	atroposHash := consensus.EventHash{}
+	if len(candidates) != 0 {
+		atroposHash = candidates[len(candidates)-1]
+	}
+	if trace != nil {
+		trace.Roots = candidates
+		defer func() { trace.Atropos = atroposHash }()
 	}
 	// tiebreaker can simply pick the first encountered root that is forkless caused by any event.
 	// It is easiest to look for any vote (forkless cause) by frame + 1 roots.
 	// Due to forkless cause semantics, only one forkless-caused root can exist with specified frame and validator number.
-	if len(candidateMap) > 1 {
-		judgeRoots := el.getFrameRoots(frame + 1)
-		for atroposCandidateHash := range candidateMap {
+	if len(candidates) > 1 {
+		judgeRoots, err := el.getFrameRoots(frame + 1)
+		if err != nil {
+			return consensus.EventHash{}, err
+		}
+		for _, atroposCandidateHash := range candidates {
 			for _, judge := range judgeRoots {
-				if el.forklessCauses(judge.RootHash, atroposCandidateHash) {
-					return atroposCandidateHash
+				forklessCaused, err := el.forklessCauses(judge.RootHash, atroposCandidateHash)
+				if err != nil {
+					return consensus.EventHash{}, err
+				}
+				if trace != nil {
+					trace.Tiebreak = append(trace.Tiebreak, TiebreakTrace{Judge: judge.RootHash, Root: atroposCandidateHash, ForklessCaused: forklessCaused})
+				}
+				if forklessCaused {
+					atroposHash = atroposCandidateHash
+					return atroposHash, nil
 				}
 			}
 		}
 	}
 
-	return atroposHash
+	return atroposHash, nil
 }
 
-func (el *election) observedRoots(root consensus.EventHash, frame consensus.Frame) []consensusstore.RootDescriptor {
+func (el *election) observedRoots(root consensus.EventHash, frame consensus.Frame) ([]consensusstore.RootDescriptor, error) {
 	observedRoots := make([]consensusstore.RootDescriptor, 0, el.validators.Len())
-	frameRoots := el.getFrameRoots(frame)
+	frameRoots, err := el.getFrameRoots(frame)
+	if err != nil {
+		return nil, err
+	}
 	for _, frameRoot := range frameRoots {
-		if el.forklessCauses(root, frameRoot.RootHash) {
+		forklessCaused, err := el.forklessCauses(root, frameRoot.RootHash)
+		if err != nil {
+			return nil, err
+		}
+		if forklessCaused {
 			observedRoots = append(observedRoots, frameRoot)
 		}
 	}
-	return observedRoots
+	return observedRoots, nil
 }
 
 func (el *election) prepareNewElectorRoot(frame consensus.Frame, validatorIdx consensus.ValidatorIndex, root consensus.EventHash) {
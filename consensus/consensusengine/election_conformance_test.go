@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// electionImplementations are the Election implementations checked by TestElection_Conformance and TestLachesisModel_Differential
+var electionImplementations = []struct {
+	name        string
+	newElection NewElectionFn
+}{
+	{
+		name: "default",
+		newElection: func(frameToDeliver consensus.Frame, validators *consensus.Validators, forklessCauseFn ForklessCauseFn, getFrameRoots GetFrameRootsFn) Election {
+			return NewElection(frameToDeliver, validators, forklessCauseFn, getFrameRoots)
+		},
+	},
+	{
+		name:        "recursive",
+		newElection: NewRecursiveElection,
+	},
+}
+
+// TestElection_Conformance checks the implementations on the sealed epochs, restarts and the order of the events,
+// the agreement with the reference model is checked by TestLachesisModel_Differential
+func TestElection_Conformance(t *testing.T) {
+	for _, impl := range electionImplementations {
+		t.Run(impl.name, func(t *testing.T) {
+			t.Run("order", func(t *testing.T) {
+				testElectionOrder(t, impl.newElection, []consensus.Weight{1, 2, 3, 4, 5}, 1)
+				testElectionOrder(t, impl.newElection, []consensus.Weight{1, 1, 1, 1, 1, 1, 1, 1}, 2)
+			})
+			t.Run("epochs", func(t *testing.T) {
+				testElectionEpochs(t, impl.newElection, []consensus.Weight{1, 2, 3, 4, 5}, 1)
+				testElectionEpochs(t, impl.newElection, []consensus.Weight{1, 1, 1, 1, 1, 1, 1, 1}, 2)
+			})
+		})
+	}
+}
+
+// testElectionOrder checks that the election decides the same blocks when the events are processed
+// in another topological order, and that every Atropos is a root of its frame
+func testElectionOrder(t *testing.T, newElection NewElectionFn, weights []consensus.Weight, cheatersCount int) {
+	assertar := assert.New(t)
+
+	config := LiteConfig()
+	config.NewElection = newElection
+	nodes := consensustest.GenNodes(len(weights))
+	lch, _, input, _ := newCoreLachesis(nodes, weights, config)
+
+	var ordered consensus.Events
+	r := rand.New(rand.NewSource(int64(len(nodes)))) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:cheatersCount], TestMaxEpochEvents, len(nodes)/2+1, 10, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+			ordered = append(ordered, e)
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if !assertar.NotEmpty(lch.blocks) {
+		return
+	}
+	for key, block := range lch.blocks {
+		atropos := input.GetEvent(block.Atropos)
+		if assertar.NotNil(atropos, "atropos of frame %d", key.Frame) {
+			assertar.Equal(key.Frame, atropos.Frame(), "frame of atropos %d", key.Frame)
+		}
+	}
+
+	// parents have lower Lamport times, so the events sorted by Lamport times are in a topological order
+	reordered := append(consensus.Events{}, ordered...)
+	sort.SliceStable(reordered, func(i, j int) bool {
+		return ByLamportCreatorHash(reordered[i], reordered[j])
+	})
+	other, _, otherInput, _ := newCoreLachesis(nodes, weights, config)
+	for _, e := range reordered {
+		otherInput.SetEvent(e)
+		assertar.NoError(other.Process(e))
+	}
+	assertar.Equal(lch.blocks, other.blocks)
+}
+
+// testElectionEpochs checks that the election is reset by the sealed epochs, and that the roots are re-voted
+// after the restarts, i.e. the restarted instance decides the same blocks as the default election
+func testElectionEpochs(t *testing.T, newElection NewElectionFn, weights []consensus.Weight, cheatersCount int) {
+	assertar := assert.New(t)
+
+	const (
+		GENERATOR = 0 // event generator
+		EXPECTED  = 1 // default election
+		RESTORED  = 2 // restarted randomly
+		epochs    = 3
+	)
+	nodes := consensustest.GenNodes(len(weights))
+	config := LiteConfig()
+	config.NewElection = newElection
+
+	generator, _, generatorInput, _ := newCoreLachesis(nodes, weights, config)
+	expected, _, expectedInput, _ := NewCoreLachesis(nodes, weights)
+	restored, _, restoredInput, _ := newCoreLachesis(nodes, weights, config)
+	inputs := []*consensustest.TestEventSource{generatorInput, expectedInput, restoredInput}
+	lchs := []*CoreLachesis{generator, expected, restored}
+
+	// seal epoch on decided frame == maxEpochBlocks
+	maxEpochBlocks := TestMaxEpochEvents / 4
+	for _, lch := range lchs {
+		lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+			if lch.store.GetLastDecidedFrame()+1 == consensus.Frame(maxEpochBlocks) {
+				return mutateValidators(lch.store.GetValidators())
+			}
+			return nil
+		}
+	}
+
+	var ordered consensus.Events
+	r := rand.New(rand.NewSource(int64(len(nodes)))) // nolint:gosec
+	for epoch := consensus.FirstEpoch; epoch <= epochs; epoch++ {
+		consensustest.ForEachRandFork(nodes, nodes[:cheatersCount], TestMaxEpochEvents, 5, 10, r, consensustest.ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				inputs[GENERATOR].SetEvent(e)
+				assertar.NoError(lchs[GENERATOR].Process(e))
+				ordered = append(ordered, e)
+			},
+			Build: func(e consensus.MutableEvent, name string) error {
+				if epoch != lchs[GENERATOR].store.GetEpoch() {
+					return errors.New("epoch already sealed, skip")
+				}
+				e.SetEpoch(epoch)
+				return lchs[GENERATOR].Build(e)
+			},
+		})
+	}
+	if !assertar.Equal(maxEpochBlocks*epochs, len(lchs[GENERATOR].blocks)) {
+		return
+	}
+
+	for _, e := range ordered {
+		if r.Intn(10) == 0 {
+			lchs[RESTORED].IndexedLachesis = restartLachesis(assertar, lchs[RESTORED])
+		}
+		for _, i := range []int{EXPECTED, RESTORED} {
+			inputs[i].SetEvent(e)
+			assertar.NoError(lchs[i].Process(e))
+		}
+		if t.Failed() {
+			return
+		}
+	}
+
+	compareBlocks(assertar, lchs[GENERATOR], lchs[RESTORED])
+	compareBlocks(assertar, lchs[EXPECTED], lchs[RESTORED])
+}
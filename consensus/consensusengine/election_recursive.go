@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
+)
+
+// recursiveElection is the classic per-frame recursive voting. It decides the same Atropoi as the default election,
+// but instead of the aggregated vote matrices it keeps the yes/no votes of every root for every undecided frame,
+// and sums the weighted votes of the observed roots frame by frame.
+// It's slower than the default election, and it's meant as a reference for the alternative election rules.
+type recursiveElection struct {
+	validators *consensus.Validators
+
+	forklessCauses ForklessCauseFn
+	getFrameRoots  GetFrameRootsFn
+
+	// roots are the voted roots of the undelivered frames, by frame
+	roots map[consensus.Frame][]consensusstore.RootDescriptor
+	// votes are the votes of the voted roots for the candidates of the undelivered frames, by root and frame
+	votes map[consensus.EventHash]map[consensus.Frame]map[consensus.ValidatorID]bool
+	// decided are the decided frames, which aren't delivered yet
+	decided        map[consensus.Frame]consensus.EventHash
+	frameToDeliver consensus.Frame
+	// electionQuorum is the doubled quorum threshold of the total weight, see QuorumThreshold.ElectionQuorum
+	electionQuorum int64
+}
+
+// NewRecursiveElection creates the classic per-frame recursive voting election.
+// It decides the same Atropoi as NewElection, and it's selected by Config.NewElection.
+func NewRecursiveElection(
+	frameToDeliver consensus.Frame,
+	validators *consensus.Validators,
+	forklessCauseFn ForklessCauseFn,
+	getFrameRoots GetFrameRootsFn,
+) Election {
+	el := &recursiveElection{
+		forklessCauses: forklessCauseFn,
+		getFrameRoots:  getFrameRoots,
+	}
+	el.ResetEpoch(frameToDeliver, validators)
+	return el
+}
+
+func (el *recursiveElection) ResetEpoch(frameToDeliver consensus.Frame, validators *consensus.Validators) {
+	el.validators = validators
+	el.roots = map[consensus.Frame][]consensusstore.RootDescriptor{}
+	el.votes = map[consensus.EventHash]map[consensus.Frame]map[consensus.ValidatorID]bool{}
+	el.decided = map[consensus.Frame]consensus.EventHash{}
+	el.frameToDeliver = frameToDeliver
+	el.electionQuorum = int64(validators.QuorumThreshold().ElectionQuorum(validators.TotalWeight()))
+}
+
+func (el *recursiveElection) VoteAndAggregate(
+	frame consensus.Frame,
+	validatorID consensus.ValidatorID,
+	rootHash consensus.EventHash,
+) ([]*AtroposDecision, error) {
+	if frame < el.frameToDeliver {
+		return []*AtroposDecision{}, nil
+	}
+	el.roots[frame] = append(el.roots[frame], consensusstore.RootDescriptor{ValidatorID: validatorID, RootHash: rootHash})
+	if frame == el.frameToDeliver {
+		return []*AtroposDecision{}, nil
+	}
+
+	observedRoots, err := el.observedRoots(rootHash, frame-1)
+	if err != nil {
+		return nil, err
+	}
+	observedRootsWeight := int64(0)
+	for _, observed := range observedRoots {
+		observedRootsWeight += int64(el.validators.Get(observed.ValidatorID))
+	}
+
+	votes := map[consensus.Frame]map[consensus.ValidatorID]bool{}
+	el.votes[rootHash] = votes
+	// the root of the next frame votes for the validators whose roots it observes
+	votes[frame-1] = map[consensus.ValidatorID]bool{}
+	for _, observed := range observedRoots {
+		votes[frame-1][observed.ValidatorID] = true
+	}
+	// the higher root votes as the weighted majority of the observed roots, and decides if the majority is large enough
+	Q := el.electionQuorum - observedRootsWeight
+	for candidatesFrame := el.frameToDeliver; candidatesFrame+1 < frame; candidatesFrame++ {
+		sums := map[consensus.ValidatorID]int64{}
+		for _, observed := range observedRoots {
+			weight := int64(el.validators.Get(observed.ValidatorID))
+			observedVotes, ok := el.votes[observed.RootHash][candidatesFrame]
+			if !ok {
+				continue
+			}
+			for _, candidate := range el.validators.IDs() {
+				if observedVotes[candidate] {
+					sums[candidate] += weight
+				} else {
+					sums[candidate] -= weight
+				}
+			}
+		}
+		votes[candidatesFrame] = map[consensus.ValidatorID]bool{}
+		for _, candidate := range el.validators.IDs() {
+			votes[candidatesFrame][candidate] = sums[candidate] >= 0
+		}
+		if _, ok := el.decided[candidatesFrame]; ok {
+			continue
+		}
+		for _, candidate := range el.validators.SortedIDs() {
+			if sums[candidate] >= Q {
+				atropos, err := el.elect(candidatesFrame, candidate)
+				if err != nil {
+					return nil, err
+				}
+				el.decided[candidatesFrame] = atropos
+				break
+			}
+			if sums[candidate] > -Q {
+				break
+			}
+		}
+	}
+
+	atropoi := []*AtroposDecision{}
+	for {
+		atropos, ok := el.decided[el.frameToDeliver]
+		if !ok {
+			break
+		}
+		atropoi = append(atropoi, &AtroposDecision{Frame: el.frameToDeliver, AtroposHash: atropos})
+		el.deliver(el.frameToDeliver)
+	}
+	return atropoi, nil
+}
+
+// elect picks the root of the validator in the frame, among the forks it's the one forkless caused by a root of the next frame
+func (el *recursiveElection) elect(frame consensus.Frame, validator consensus.ValidatorID) (consensus.EventHash, error) {
+	var candidates consensus.EventHashes
+	for _, root := range el.roots[frame] {
+		if root.ValidatorID == validator {
+			candidates = append(candidates, root.RootHash)
+		}
+	}
+	if len(candidates) == 0 {
+		return consensus.EventHash{}, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "no root of the decided validator %d in frame %d", validator, frame)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return bytes.Compare(candidates[i].Bytes(), candidates[j].Bytes()) < 0 })
+	if len(candidates) > 1 {
+		judgeRoots, err := el.getFrameRoots(frame + 1)
+		if err != nil {
+			return consensus.EventHash{}, err
+		}
+		for _, candidate := range candidates {
+			for _, judge := range judgeRoots {
+				forklessCaused, err := el.forklessCauses(judge.RootHash, candidate)
+				if err != nil {
+					return consensus.EventHash{}, err
+				}
+				if forklessCaused {
+					return candidate, nil
+				}
+			}
+		}
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+// observedRoots returns the voted roots of the frame forkless caused by the root
+func (el *recursiveElection) observedRoots(root consensus.EventHash, frame consensus.Frame) ([]consensusstore.RootDescriptor, error) {
+	frameRoots, err := el.getFrameRoots(frame)
+	if err != nil {
+		return nil, err
+	}
+	observedRoots := make([]consensusstore.RootDescriptor, 0, el.validators.Len())
+	for _, frameRoot := range frameRoots {
+		forklessCaused, err := el.forklessCauses(root, frameRoot.RootHash)
+		if err != nil {
+			return nil, err
+		}
+		if forklessCaused {
+			observedRoots = append(observedRoots, frameRoot)
+		}
+	}
+	return observedRoots, nil
+}
+
+// deliver drops the votes for the delivered frame, and the roots which don't vote for the undelivered frames
+func (el *recursiveElection) deliver(frame consensus.Frame) {
+	for _, root := range el.roots[frame] {
+		delete(el.votes, root.RootHash)
+	}
+	delete(el.roots, frame)
+	delete(el.decided, frame)
+	for _, votes := range el.votes {
+		delete(votes, frame)
+	}
+	el.frameToDeliver++
+}
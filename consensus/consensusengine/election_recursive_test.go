@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
+)
+
+func TestRecursiveElection_ElectMissingRoot(t *testing.T) {
+	assertar := assert.New(t)
+
+	validators := consensus.ArrayToValidators([]consensus.ValidatorID{1, 2, 3}, []consensus.Weight{1, 1, 1})
+	el := NewRecursiveElection(1, validators,
+		func(a, b consensus.EventHash) (bool, error) { return true, nil },
+		func(f consensus.Frame) ([]consensusstore.RootDescriptor, error) { return nil, nil },
+	).(*recursiveElection)
+
+	_, err := el.VoteAndAggregate(1, 1, consensus.EventHash{1})
+	assertar.NoError(err)
+	atropos, err := el.elect(1, 1)
+	assertar.NoError(err)
+	assertar.Equal(consensus.EventHash{1}, atropos)
+
+	// the decided validator has no voted root in the frame
+	_, err = el.elect(1, 2)
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+}
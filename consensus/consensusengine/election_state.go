@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bytes"
+	"container/heap"
+	"sort"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
+)
+
+// Checkpoint returns a snapshot of the election vote state.
+// Votes are sorted by (frame, validator, root hash), so that the snapshot is deterministic.
+// Votes of the delivered frames are omitted, as they don't participate in the election anymore.
+func (el *election) Checkpoint() (*consensusstore.ElectionState, error) {
+	state := &consensusstore.ElectionState{
+		FrameToDeliver: el.frameToDeliver,
+	}
+	for frame, frameVotes := range el.vote {
+		if frame < el.frameToDeliver {
+			continue
+		}
+		for validatorIdx, rootVotes := range frameVotes {
+			for rootHash, rootContext := range rootVotes {
+				var voteMatrix []uint64
+				if len(rootContext.voteMatrix) != 0 {
+					voteMatrix = make([]uint64, len(rootContext.voteMatrix))
+				}
+				for i, v := range rootContext.voteMatrix {
+					voteMatrix[i] = uint64(v)
+				}
+				state.Votes = append(state.Votes, consensusstore.RootVote{
+					Frame:                frame,
+					ValidatorID:          el.validators.GetID(consensus.ValidatorIndex(validatorIdx)),
+					RootHash:             rootHash,
+					FrameToDeliverOffset: rootContext.frameToDeliverOffset,
+					VoteMatrix:           voteMatrix,
+				})
+			}
+		}
+	}
+	sort.Slice(state.Votes, func(i, j int) bool {
+		a, b := state.Votes[i], state.Votes[j]
+		if a.Frame != b.Frame {
+			return a.Frame < b.Frame
+		}
+		if a.ValidatorID != b.ValidatorID {
+			return a.ValidatorID < b.ValidatorID
+		}
+		return bytes.Compare(a.RootHash.Bytes(), b.RootHash.Bytes()) < 0
+	})
+
+	// decided frames were cleaned up from the vote map, but their roots were voted already
+	decided := make([]*AtroposDecision, len(el.atroposDeliveryBuffer.container))
+	copy(decided, el.atroposDeliveryBuffer.container)
+	sort.Slice(decided, func(i, j int) bool { return decided[i].Frame < decided[j].Frame })
+	for _, decision := range decided {
+		state.Decided = append(state.Decided, consensusstore.DecidedAtropos{
+			Frame:       decision.Frame,
+			AtroposHash: decision.AtroposHash,
+		})
+		frameRoots, err := el.getFrameRoots(decision.Frame)
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range frameRoots {
+			state.VotedRoots = append(state.VotedRoots, root.RootHash)
+		}
+	}
+	return state, nil
+}
+
+// Restore replaces the election vote state with the snapshot.
+// The election must be already reset to the epoch of the snapshot.
+func (el *election) Restore(state *consensusstore.ElectionState) {
+	el.frameToDeliver = state.FrameToDeliver
+	el.vote = make(map[consensus.Frame][]map[consensus.EventHash]*rootVoteContext)
+	for _, v := range state.Votes {
+		var voteMatrix []int64
+		if len(v.VoteMatrix) != 0 {
+			voteMatrix = make([]int64, len(v.VoteMatrix))
+			for i, x := range v.VoteMatrix {
+				voteMatrix[i] = int64(x)
+			}
+		}
+		if _, ok := el.vote[v.Frame]; !ok {
+			el.vote[v.Frame] = make([]map[consensus.EventHash]*rootVoteContext, el.validatorCount)
+		}
+		validatorIdx := el.validatorIDMap[v.ValidatorID]
+		if el.vote[v.Frame][validatorIdx] == nil {
+			el.vote[v.Frame][validatorIdx] = make(map[consensus.EventHash]*rootVoteContext)
+		}
+		el.vote[v.Frame][validatorIdx][v.RootHash] = &rootVoteContext{
+			frameToDeliverOffset: v.FrameToDeliverOffset,
+			voteMatrix:           voteMatrix,
+		}
+	}
+	el.atroposDeliveryBuffer = NewAtroposHeap()
+	for _, d := range state.Decided {
+		heap.Push(el.atroposDeliveryBuffer, &AtroposDecision{d.Frame, d.AtroposHash})
+	}
+}
@@ -258,15 +258,15 @@ func testVoteAndAggregate(
 	}
 	validators := validatorsBuilder.Build()
 
-	forklessCauseFn := func(a consensus.EventHash, b consensus.EventHash) bool {
+	forklessCauseFn := func(a consensus.EventHash, b consensus.EventHash) (bool, error) {
 		edge := fakeEdge{
 			from: a,
 			to:   b,
 		}
-		return state.edges[edge]
+		return state.edges[edge], nil
 	}
-	getFrameRootsFn := func(f consensus.Frame) []consensusstore.RootDescriptor {
-		return state.frameRoots[f]
+	getFrameRootsFn := func(f consensus.Frame) ([]consensusstore.RootDescriptor, error) {
+		return state.frameRoots[f], nil
 	}
 
 	// re-order events randomly, preserving parents order
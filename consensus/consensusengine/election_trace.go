@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// ElectionTracer receives a trace of every root vote of the election.
+// It's called synchronously, with the same concurrency guarantees as the consensus callbacks.
+type ElectionTracer func(trace ElectionTrace)
+
+// Results of a candidate in CandidateTrace.
+const (
+	CandidateDecided   = "decided"
+	CandidateRejected  = "rejected"
+	CandidateUndecided = "undecided"
+)
+
+// ElectionTrace is a structured record of a root vote and of the decisions it led to.
+// Votes are keyed by the validator ID of the voted roots, i.e. of the candidates.
+type ElectionTrace struct {
+	Epoch     consensus.Epoch       `json:"epoch"`
+	Frame     consensus.Frame       `json:"frame"`
+	Validator consensus.ValidatorID `json:"validator"`
+	Root      consensus.EventHash   `json:"root"`
+	// DirectVotes are the votes for the roots of the previous frame: 1 if the root is forkless caused, -1 otherwise
+	DirectVotes map[consensus.ValidatorID]int64 `json:"directVotes"`
+	// ObservedWeight is the weight of the forkless caused roots of the previous frame
+	ObservedWeight consensus.Weight `json:"observedWeight"`
+	// Q is the threshold of the aggregated votes, which decides a candidate
+	Q int64 `json:"q"`
+	// Aggregated are the aggregated votes of the forkless caused roots for the undecided frames
+	Aggregated []FrameVotesTrace `json:"aggregated"`
+	// Candidates are the candidates checked by the vote, in the order of the checks
+	Candidates []CandidateTrace `json:"candidates,omitempty"`
+}
+
+// FrameVotesTrace are the aggregated votes for the candidates of a frame.
+type FrameVotesTrace struct {
+	Frame consensus.Frame                 `json:"frame"`
+	Votes map[consensus.ValidatorID]int64 `json:"votes"`
+}
+
+// CandidateTrace is the result of a candidate check.
+type CandidateTrace struct {
+	Frame     consensus.Frame       `json:"frame"`
+	Validator consensus.ValidatorID `json:"validator"`
+	Votes     int64                 `json:"votes"`
+	Result    string                `json:"result"`
+	// Elect is set if the candidate is decided
+	Elect *ElectTrace `json:"elect,omitempty"`
+}
+
+// ElectTrace is the choice of Atropos among the roots of the decided candidate.
+type ElectTrace struct {
+	// Roots are the roots of the candidate, more than one in the case of a fork
+	Roots consensus.EventHashes `json:"roots"`
+	// Tiebreak are the forkless cause checks made to choose among the forks
+	Tiebreak []TiebreakTrace     `json:"tiebreak,omitempty"`
+	Atropos  consensus.EventHash `json:"atropos"`
+}
+
+// TiebreakTrace is a forkless cause check of a fork by a root of the next frame.
+type TiebreakTrace struct {
+	Judge          consensus.EventHash `json:"judge"`
+	Root           consensus.EventHash `json:"root"`
+	ForklessCaused bool                `json:"forklessCaused"`
+}
+
+// ElectionTraceRecorder is an ElectionTracer which keeps the traces in memory.
+// It's safe for concurrent use.
+type ElectionTraceRecorder struct {
+	mu     sync.Mutex
+	traces []ElectionTrace
+}
+
+// Trace records the trace, it's to be used as Config.ElectionTracer.
+func (r *ElectionTraceRecorder) Trace(trace ElectionTrace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, trace)
+}
+
+// Traces returns the recorded traces.
+func (r *ElectionTraceRecorder) Traces() []ElectionTrace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(make([]ElectionTrace, 0, len(r.traces)), r.traces...)
+}
+
+// WriteJSON writes the recorded traces as JSON lines, one trace per line,
+// so that the traces of different nodes can be compared with a line diff.
+func (r *ElectionTraceRecorder) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, trace := range r.Traces() {
+		if err := enc.Encode(trace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadElectionTraces reads the traces written by ElectionTraceRecorder.WriteJSON.
+func ReadElectionTraces(r io.Reader) ([]ElectionTrace, error) {
+	var traces []ElectionTrace
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for dec.More() {
+		var trace ElectionTrace
+		if err := dec.Decode(&trace); err != nil {
+			return nil, err
+		}
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+// traceElection fills the epoch of the trace and passes it to the configured tracer
+func (p *Orderer) traceElection(trace ElectionTrace) {
+	trace.Epoch = p.store.GetEpoch()
+	p.config.ElectionTracer(trace)
+}
+
+// traceVote starts the trace of the root vote
+func (el *election) traceVote(frame consensus.Frame, validatorID consensus.ValidatorID, root consensus.EventHash, directVotes []int64, observedWeight int64, aggregated []int64) *ElectionTrace {
+	trace := &ElectionTrace{
+		Frame:          frame,
+		Validator:      validatorID,
+		Root:           root,
+		DirectVotes:    el.traceVotes(directVotes),
+		ObservedWeight: consensus.Weight(observedWeight),
+		Aggregated:     make([]FrameVotesTrace, 0, len(aggregated)/int(el.validatorCount)),
+	}
+	for offset := 0; offset < len(aggregated); offset += int(el.validatorCount) {
+		trace.Aggregated = append(trace.Aggregated, FrameVotesTrace{
+			Frame: el.frameToDeliver + consensus.Frame(offset)/el.validatorCount,
+			Votes: el.traceVotes(aggregated[offset : offset+int(el.validatorCount)]),
+		})
+	}
+	return trace
+}
+
+// traceVotes converts the votes indexed by the validator index into the votes keyed by the validator ID
+func (el *election) traceVotes(votes []int64) map[consensus.ValidatorID]int64 {
+	res := make(map[consensus.ValidatorID]int64, len(votes))
+	for i, vote := range votes {
+		res[el.validators.GetID(consensus.ValidatorIndex(i))] = vote
+	}
+	return res
+}
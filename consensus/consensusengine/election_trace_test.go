@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestElectionTracer(t *testing.T) {
+	assertar := assert.New(t)
+
+	weights := []consensus.Weight{1, 2, 3, 4, 5}
+	nodes := consensustest.GenNodes(len(weights))
+	lchs := make([]*CoreLachesis, 0, 2)
+	inputs := make([]*consensustest.TestEventSource, 0, 2)
+	recorders := make([]*ElectionTraceRecorder, 0, 2)
+	for i := 0; i < 2; i++ {
+		recorder := &ElectionTraceRecorder{}
+		config := LiteConfig()
+		config.ElectionTracer = recorder.Trace
+		lch, _, input, _ := newCoreLachesis(nodes, weights, config)
+		lchs = append(lchs, lch)
+		inputs = append(inputs, input)
+		recorders = append(recorders, recorder)
+	}
+
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:1], TestMaxEpochEvents, len(nodes), 10, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			for i, lch := range lchs {
+				inputs[i].SetEvent(e)
+				assertar.NoError(lch.Process(e))
+			}
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lchs[0].Build(e)
+		},
+	})
+	if !assertar.NotEmpty(lchs[0].blocks) {
+		return
+	}
+
+	// the same events order results in the same traces
+	var outputs [2]bytes.Buffer
+	for i, recorder := range recorders {
+		assertar.NoError(recorder.WriteJSON(&outputs[i]))
+	}
+	assertar.NotZero(outputs[0].Len())
+	assertar.Equal(outputs[0].String(), outputs[1].String())
+
+	traces, err := ReadElectionTraces(bytes.NewReader(outputs[0].Bytes()))
+	assertar.NoError(err)
+	assertar.Equal(recorders[0].Traces(), traces)
+
+	// every block is traced as a decided candidate
+	atropoi := map[consensus.Frame]consensus.EventHash{}
+	for _, trace := range traces {
+		assertar.Equal(consensus.FirstEpoch, trace.Epoch)
+		assertar.Len(trace.DirectVotes, len(nodes))
+		for _, votes := range trace.Aggregated {
+			assertar.Less(votes.Frame, trace.Frame-1)
+		}
+		for i, candidate := range trace.Candidates {
+			switch candidate.Result {
+			case CandidateDecided:
+				assertar.GreaterOrEqual(candidate.Votes, trace.Q)
+				if assertar.NotNil(candidate.Elect) {
+					assertar.Contains(candidate.Elect.Roots, candidate.Elect.Atropos)
+					atropoi[candidate.Frame] = candidate.Elect.Atropos
+				}
+			case CandidateRejected:
+				assertar.LessOrEqual(candidate.Votes, -trace.Q)
+			case CandidateUndecided:
+				// nothing is checked after an undecided candidate of the frame
+				if i+1 < len(trace.Candidates) {
+					assertar.NotEqual(candidate.Frame, trace.Candidates[i+1].Frame)
+				}
+			}
+		}
+	}
+	assertar.Len(atropoi, len(lchs[0].blocks))
+	for key, block := range lchs[0].blocks {
+		assertar.Equal(block.Atropos, atropoi[key.Frame])
+	}
+}
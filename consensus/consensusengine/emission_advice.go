@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// EmissionAdvice describes a new event, which a validator may emit on top of its self-parent and the heads.
+// Emitters may throttle the emission until the event would be a root, or until its Progress grows.
+type EmissionAdvice struct {
+	// Frame is the frame of the new event
+	Frame consensus.Frame
+	// Root is true if the new event would be a root, i.e. its frame is higher than the frame of the self-parent
+	Root bool
+	// Progress is the weight of the validators whose roots of the highest frame of the parents are forkless caused
+	// by the new event. The new event is a root of the next frame once the Progress reaches the Quorum.
+	Progress consensus.Weight
+	Quorum   consensus.Weight
+}
+
+// AdviseEmission calculates the EmissionAdvice for a new event of the self-parent's creator, whose parents are
+// the self-parent and the heads. The self-parent and the heads must be processed already.
+// The zero self-parent stands for the first event of the validator in the epoch, which is always a root of the first frame.
+// The new event is indexed temporarily, like by Build.
+// AdviseEmission is not safe for concurrent use.
+func (p *IndexedLachesis) AdviseEmission(selfParent consensus.EventHash, heads consensus.EventHashes) (*EmissionAdvice, error) {
+	if selfParent.IsZero() {
+		for _, head := range heads {
+			if p.Input.GetEvent(head) == nil {
+				return nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "head %s not found", head.String())
+			}
+		}
+		return &EmissionAdvice{
+			Frame:  consensus.FirstFrame,
+			Root:   true,
+			Quorum: p.store.GetValidators().Quorum(),
+		}, nil
+	}
+	sp := p.Input.GetEvent(selfParent)
+	if sp == nil {
+		return nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "self-parent %s not found", selfParent.String())
+	}
+	e := &consensus.MutableBaseEvent{}
+	e.SetEpoch(p.store.GetEpoch())
+	e.SetCreator(sp.Creator())
+	e.SetSeq(sp.Seq() + 1)
+	e.SetLamport(sp.Lamport() + 1)
+	parents := consensus.EventHashes{selfParent}
+	for _, head := range heads {
+		if head == selfParent {
+			continue
+		}
+		parent := p.Input.GetEvent(head)
+		if parent == nil {
+			return nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "head %s not found", head.String())
+		}
+		parents = append(parents, head)
+		e.SetLamport(max(e.Lamport(), parent.Lamport()+1))
+	}
+	e.SetParents(parents)
+	e.SetID(p.uniqueDirtyID.sample())
+
+	defer p.DagIndexer.DropNotFlushed()
+	err := p.DagIndexer.Add(e)
+	if err != nil {
+		return nil, p.fail(err)
+	}
+
+	return p.Lachesis.adviseEmission(e)
+}
+
+// adviseEmission calculates the EmissionAdvice for the indexed event, which isn't processed
+func (p *Orderer) adviseEmission(e consensus.Event) (*EmissionAdvice, error) {
+	selfParentFrame, frame, err := p.calcFrameIdx(e)
+	if err != nil {
+		return nil, p.fail(err)
+	}
+	parentsFrame, err := p.highestFrame(e.Parents())
+	if err != nil {
+		return nil, p.fail(err)
+	}
+	observedCounter, err := p.forklessCausedCounterOn(e, parentsFrame, false)
+	if err != nil {
+		return nil, p.fail(err)
+	}
+	return &EmissionAdvice{
+		Frame:    frame,
+		Root:     frame != selfParentFrame,
+		Progress: observedCounter.Sum(),
+		Quorum:   p.store.GetValidators().Quorum(),
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestIndexedLachesis_AdviseEmission(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	weights := []consensus.Weight{1, 2, 3, 4, 5}
+	lch, store, input, _ := NewCoreLachesis(nodes, weights)
+	synced := NewSyncLachesis(lch.IndexedLachesis)
+
+	roots, progressed := 0, 0
+	r := rand.New(rand.NewSource(0)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:1], 50, 3, 5, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(synced.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			selfParent, heads := consensus.EventHash{}, e.Parents()
+			if sp := e.SelfParent(); sp != nil {
+				selfParent, heads = *sp, e.Parents()[1:]
+			}
+			advice, err := synced.AdviseEmission(selfParent, heads)
+			if !assertar.NoError(err) {
+				return err
+			}
+			if err := synced.Build(e); err != nil {
+				return err
+			}
+
+			// the advice matches the built event
+			selfParentFrame := consensus.Frame(0)
+			if !selfParent.IsZero() {
+				selfParentFrame = input.GetEvent(selfParent).Frame()
+			}
+			assertar.Equal(e.Frame(), advice.Frame, name)
+			assertar.Equal(e.Frame() != selfParentFrame, advice.Root, name)
+			assertar.Equal(store.GetValidators().Quorum(), advice.Quorum)
+			parentsFrame := consensus.Frame(0)
+			for _, p := range e.Parents() {
+				parentsFrame = max(parentsFrame, input.GetEvent(p).Frame())
+			}
+			if !selfParent.IsZero() {
+				assertar.Equal(e.Frame() > parentsFrame, advice.Progress >= advice.Quorum, name)
+			}
+			if advice.Root {
+				roots++
+			}
+			if advice.Progress > 0 && advice.Progress < advice.Quorum {
+				progressed++
+			}
+			return nil
+		},
+	})
+	assertar.NotZero(roots)
+	assertar.NotZero(progressed)
+
+	_, err := synced.AdviseEmission(consensus.EventHash{1}, nil)
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+	last := input.GetEvent(lch.blocks[lch.lastBlock].Atropos)
+	_, err = synced.AdviseEmission(last.ID(), consensus.EventHashes{{1}})
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+	// the self-parent among the heads is ignored
+	advice, err := synced.AdviseEmission(last.ID(), consensus.EventHashes{last.ID()})
+	assertar.NoError(err)
+	assertar.Equal(last.Frame(), advice.Frame)
+
+	// the first event of the validator in the epoch is a root of the first frame, but its heads must be known
+	advice, err = synced.AdviseEmission(consensus.EventHash{}, consensus.EventHashes{last.ID()})
+	assertar.NoError(err)
+	assertar.Equal(consensus.FirstFrame, advice.Frame)
+	assertar.True(advice.Root)
+	_, err = synced.AdviseEmission(consensus.EventHash{}, consensus.EventHashes{last.ID(), {1}})
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+}
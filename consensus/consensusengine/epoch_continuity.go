@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// CheckEpochsContinuously recalculates the consecutive epochs by a single engine, unlike CheckEpoch which
+// starts every epoch from the genesis. Every epoch but the last one is sealed at its last Atropos in the event DB,
+// with the validators of the next epoch, so that the next epoch is recalculated by the engine after the sealing.
+// The events of a sealed epoch following the sealing in the Lamport order aren't processed, and the roots are checked
+// among the processed events only, as the epoch store is reset on the sealing. The last epoch is checked by CheckEpoch.
+// The results end with the first diverged epoch, as the following epochs can't be recalculated after it.
+func CheckEpochsContinuously(conn *sql.DB, epochMin, epochMax consensus.Epoch) []*EpochCheckResult {
+	results := make([]*EpochCheckResult, 0, epochMax-epochMin+1)
+	var (
+		testLachesis *CoreLachesis
+		eventStore   *consensustest.TestEventSource
+	)
+	for epoch := epochMin; epoch <= epochMax; epoch++ {
+		result := &EpochCheckResult{Epoch: epoch}
+		results = append(results, result)
+
+		validators, weights, err := getValidator(conn, epoch)
+		if err != nil {
+			result.diverge(DivergenceError, 0, err)
+			break
+		}
+		if testLachesis == nil {
+			if len(validators) == 0 {
+				// no validators to start from
+				continue
+			}
+			if testLachesis, eventStore, err = newEpochLachesis(epoch, validators, weights); err != nil {
+				result.diverge(DivergenceError, 0, err)
+				break
+			}
+		}
+		if got := testLachesis.store.GetEpoch(); got != epoch {
+			result.diverge(DivergenceError, 0, fmt.Errorf("engine is at epoch %d instead of %d", got, epoch))
+			break
+		}
+		orderedEvents, eventMap, err := getEvents(conn, epoch)
+		if err != nil {
+			result.diverge(DivergenceError, 0, err)
+			break
+		}
+		expectedAtropoi, err := getAtropoi(conn, epoch)
+		if err != nil {
+			result.diverge(DivergenceError, 0, err)
+			break
+		}
+		expected, err := getExpectedBlocks(conn, epoch)
+		if err != nil {
+			result.diverge(DivergenceError, 0, err)
+			break
+		}
+		if epoch == epochMax {
+			checkEpoch(result, testLachesis, eventStore, eventMap, orderedEvents, expectedAtropoi, expected)
+			break
+		}
+		next, err := getNextValidators(conn, epoch)
+		if err != nil {
+			result.diverge(DivergenceError, 0, err)
+			break
+		}
+		if checkSealedEpoch(result, testLachesis, eventStore, eventMap, orderedEvents, expectedAtropoi, expected, next).Divergence != nil {
+			break
+		}
+	}
+	return results
+}
+
+// getNextValidators returns the validators of the epoch following the epoch, to seal the epoch with
+func getNextValidators(conn *sql.DB, epoch consensus.Epoch) (*consensus.Validators, error) {
+	ids, weights, err := getValidator(conn, epoch+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no validators of epoch %d to seal epoch %d with", epoch+1, epoch)
+	}
+	builder := consensus.NewBuilder()
+	for i, id := range ids {
+		builder.Set(id, weights[i])
+	}
+	return builder.Build(), nil
+}
+
+// checkSealedEpoch is checkEpoch which seals the epoch at the last expected Atropos with the next validators.
+// The blocks are compared as they are decided, as the epoch store is reset after the sealing.
+func checkSealedEpoch(result *EpochCheckResult, testLachesis *CoreLachesis, eventStore *consensustest.TestEventSource, eventMap map[consensus.EventHash]*dbEvent, orderedEvents []*dbEvent, expectedAtropoi []consensus.EventHash, expected *expectedBlocks, next *consensus.Validators) *EpochCheckResult {
+	epoch := result.Epoch
+	result.Events = len(orderedEvents)
+	result.ExpectedAtropoi = len(expectedAtropoi)
+	result.Skipped = expected.skipped
+	if len(expectedAtropoi) == 0 {
+		return result.diverge(DivergenceAtropos, 0, fmt.Errorf("no atropoi to seal epoch %d at", epoch))
+	}
+
+	processed := consensus.EventHashSet{}
+	var blockDivergence *Divergence
+	testLachesis.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		idx := result.RecalculatedAtropoi
+		result.RecalculatedAtropoi++
+		if blockDivergence != nil {
+			return nil
+		}
+		if kind, err := checkBlock(testLachesis, epoch, idx, block, expectedAtropoi[idx], expected, eventMap); err != nil {
+			blockDivergence = &Divergence{Kind: kind, Position: idx, Message: err.Error()}
+			result.err = err
+			return nil
+		}
+		if idx != len(expectedAtropoi)-1 {
+			return nil
+		}
+		// the epoch store is still available before the sealing
+		roots, err := countRoots(testLachesis)
+		if err != nil {
+			blockDivergence = &Divergence{Kind: DivergenceError, Position: idx, Message: err.Error()}
+			result.err = err
+			return nil
+		}
+		result.Roots = roots
+		if expected.roots != nil {
+			if frame, err := checkRoots(testLachesis, epoch, eventMap, processedRoots(expected.roots, processed)); err != nil {
+				blockDivergence = &Divergence{Kind: DivergenceRoots, Position: int(frame), Message: err.Error()}
+				result.err = err
+				return nil
+			}
+		}
+		return next
+	}
+	defer func() {
+		testLachesis.applyBlock = nil
+	}()
+
+	for i, event := range orderedEvents {
+		if testLachesis.store.GetEpoch() != epoch {
+			result.Unprocessed = len(orderedEvents) - i
+			break
+		}
+		processed.Add(event.hash)
+		if err := ingestEvent(testLachesis, eventStore, event); err != nil {
+			if blockDivergence != nil {
+				break
+			}
+			return result.diverge(DivergenceEvent, i, err)
+		}
+		if blockDivergence != nil {
+			break
+		}
+	}
+	if blockDivergence != nil {
+		result.Divergence = blockDivergence
+		return result
+	}
+	if testLachesis.store.GetEpoch() == epoch {
+		return result.diverge(DivergenceAtropos, result.RecalculatedAtropoi, fmt.Errorf("incorrect number of atropoi recalculated for epoch %d, expected: %d, got: %d", epoch, len(expectedAtropoi), result.RecalculatedAtropoi))
+	}
+	return result
+}
+
+// processedRoots returns the expected roots which are processed
+func processedRoots(roots map[consensus.Frame]consensus.EventHashSet, processed consensus.EventHashSet) map[consensus.Frame]consensus.EventHashSet {
+	res := make(map[consensus.Frame]consensus.EventHashSet, len(roots))
+	for frame, frameRoots := range roots {
+		for root := range frameRoots {
+			if !processed.Contains(root) {
+				continue
+			}
+			if res[frame] == nil {
+				res[frame] = consensus.EventHashSet{}
+			}
+			res[frame].Add(root)
+		}
+	}
+	return res
+}
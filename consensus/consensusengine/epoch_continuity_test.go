@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+func TestCheckEpochsContinuously(t *testing.T) {
+	config := DefaultEventDBConfig()
+	config.Epochs = 4
+
+	t.Run("match", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openEmptyEventDB(t)
+		if !assertar.NoError(GenerateEventDB(conn, config)) {
+			return
+		}
+
+		results := CheckEpochsContinuously(conn, 1, 4)
+		if !assertar.Len(results, 4) {
+			return
+		}
+		for _, result := range results {
+			assertar.NoError(result.Err(), "epoch %d", result.Epoch)
+			assertar.Equal(result.ExpectedAtropoi, result.RecalculatedAtropoi, "epoch %d", result.Epoch)
+			assertar.NotZero(result.Roots, "epoch %d", result.Epoch)
+		}
+		// the epochs may also be started from the middle
+		results = CheckEpochsContinuously(conn, 2, 3)
+		if assertar.Len(results, 2) {
+			assertar.NoError(results[1].Err())
+		}
+	})
+
+	t.Run("missing validators", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openEmptyEventDB(t)
+		if !assertar.NoError(GenerateEventDB(conn, config)) {
+			return
+		}
+		_, err := conn.Exec(`DELETE FROM Validator WHERE EpochId = 3`)
+		assertar.NoError(err)
+
+		results := CheckEpochsContinuously(conn, 1, 4)
+		if assertar.Len(results, 2) {
+			assertar.NoError(results[0].Err())
+			assertar.Error(results[1].Err())
+			assertar.Equal(DivergenceError, results[1].Divergence.Kind)
+		}
+	})
+
+	t.Run("atropos", func(t *testing.T) {
+		assertar := assert.New(t)
+		conn := openEmptyEventDB(t)
+		if !assertar.NoError(GenerateEventDB(conn, config)) {
+			return
+		}
+		// the third atropos of the second epoch is expected to be a different event of the same frame,
+		// the event IDs are ordered by frame, so the order of the atropoi is kept
+		_, err := conn.Exec(`
+			WITH third AS (
+				SELECT e.EventId, e.FrameId FROM Atropos a JOIN Event e ON a.AtroposId = e.EventId
+				WHERE e.EpochId = 2 ORDER BY a.AtroposId LIMIT 1 OFFSET 2
+			)
+			UPDATE Atropos SET AtroposId = (
+				SELECT MIN(e.EventId) FROM Event e, third WHERE e.EpochId = 2 AND e.FrameId = third.FrameId AND e.EventId != third.EventId
+			)
+			WHERE AtroposId = (SELECT EventId FROM third)`)
+		assertar.NoError(err)
+
+		results := CheckEpochsContinuously(conn, 1, 4)
+		if assertar.Len(results, 2) {
+			assertar.NoError(results[0].Err())
+			if assertar.NotNil(results[1].Divergence) {
+				assertar.Equal(consensus.Epoch(2), results[1].Epoch)
+				assertar.Equal(DivergenceAtropos, results[1].Divergence.Kind)
+				assertar.Equal(2, results[1].Divergence.Position)
+			}
+		}
+	})
+}
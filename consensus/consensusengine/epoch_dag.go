@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// Format and version of the files written by EpochDAG.Write.
+const (
+	EpochDAGFormat  = "lachesis-epoch-dag"
+	EpochDAGVersion = 1
+)
+
+var (
+	ErrUnknownDAGFormat = errors.New("unknown epoch DAG format")
+	ErrMalformedDAG     = errors.New("malformed epoch DAG")
+)
+
+// EpochDAG is a portable snapshot of an epoch of the event DB.
+// It holds everything needed to recalculate the epoch, so that it can be replayed without the DB.
+type EpochDAG struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	Epoch      consensus.Epoch `json:"epoch"`
+	Validators []DAGValidator  `json:"validators"`
+	// Events are in the order of processing, i.e. ordered by Lamport timestamp
+	Events []DAGEvent `json:"events"`
+	// Atropoi are the expected atropoi, in the order of the decided blocks
+	Atropoi consensus.EventHashes `json:"atropoi"`
+}
+
+// DAGValidator is a validator of the epoch.
+type DAGValidator struct {
+	ID     consensus.ValidatorID `json:"id"`
+	Weight consensus.Weight      `json:"weight"`
+}
+
+// DAGEvent is an event of the epoch, with the frame assigned by the DB.
+type DAGEvent struct {
+	ID      consensus.EventHash   `json:"id"`
+	Creator consensus.ValidatorID `json:"creator"`
+	Seq     consensus.Seq         `json:"seq"`
+	Lamport consensus.Lamport     `json:"lamport"`
+	Frame   consensus.Frame       `json:"frame"`
+	// Parents are the IDs of the parents, the self-parent first
+	Parents consensus.EventHashes `json:"parents"`
+}
+
+// ExportEpoch loads the epoch from the event DB.
+func ExportEpoch(conn *sql.DB, epoch consensus.Epoch) (*EpochDAG, error) {
+	validators, weights, err := getValidator(conn, epoch)
+	if err != nil {
+		return nil, err
+	}
+	eventsOrdered, _, err := getEvents(conn, epoch)
+	if err != nil {
+		return nil, err
+	}
+	atropoi, err := getAtropoi(conn, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	dag := &EpochDAG{
+		Format:     EpochDAGFormat,
+		Version:    EpochDAGVersion,
+		Epoch:      epoch,
+		Validators: make([]DAGValidator, 0, len(validators)),
+		Events:     make([]DAGEvent, 0, len(eventsOrdered)),
+		Atropoi:    atropoi,
+	}
+	for i, validator := range validators {
+		dag.Validators = append(dag.Validators, DAGValidator{ID: validator, Weight: weights[i]})
+	}
+	for _, event := range eventsOrdered {
+		dag.Events = append(dag.Events, DAGEvent{
+			ID:      event.hash,
+			Creator: event.validatorId,
+			Seq:     event.seq,
+			Lamport: event.lamportTs,
+			Frame:   event.frame,
+			Parents: event.parents,
+		})
+	}
+	return dag, nil
+}
+
+// Write writes the DAG as JSON.
+func (d *EpochDAG) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(d)
+}
+
+// ReadEpochDAG reads the DAG written by EpochDAG.Write.
+func ReadEpochDAG(r io.Reader) (*EpochDAG, error) {
+	dag := &EpochDAG{}
+	if err := json.NewDecoder(r).Decode(dag); err != nil {
+		return nil, err
+	}
+	if dag.Format != EpochDAGFormat || dag.Version != EpochDAGVersion {
+		return nil, errors.Wrapf(ErrUnknownDAGFormat, "%q version %d", dag.Format, dag.Version)
+	}
+	return dag, nil
+}
+
+// ReplayEpoch recalculates the exported epoch and compares it with the DAG, the same way as CheckEpoch.
+func ReplayEpoch(dag *EpochDAG) *EpochCheckResult {
+	result := &EpochCheckResult{Epoch: dag.Epoch}
+	eventsOrdered, eventMap, err := dag.dbEvents()
+	if err != nil {
+		return result.diverge(DivergenceError, 0, err)
+	}
+
+	var (
+		testLachesis *CoreLachesis
+		eventStore   *consensustest.TestEventSource
+	)
+	if len(dag.Validators) != 0 {
+		validators := make([]consensus.ValidatorID, 0, len(dag.Validators))
+		weights := make([]consensus.Weight, 0, len(dag.Validators))
+		for _, validator := range dag.Validators {
+			validators = append(validators, validator.ID)
+			weights = append(weights, validator.Weight)
+		}
+		testLachesis, eventStore, err = newEpochLachesis(dag.Epoch, validators, weights)
+		if err != nil {
+			return result.diverge(DivergenceError, 0, err)
+		}
+	}
+	// the optional checks of the event DB aren't exported
+	return checkEpoch(result, testLachesis, eventStore, eventMap, eventsOrdered, dag.Atropoi, &expectedBlocks{})
+}
+
+// dbEvents converts the events, checking that every event is unique and is preceded by its parents
+func (d *EpochDAG) dbEvents() ([]*dbEvent, map[consensus.EventHash]*dbEvent, error) {
+	eventMap := make(map[consensus.EventHash]*dbEvent, len(d.Events))
+	eventsOrdered := make([]*dbEvent, 0, len(d.Events))
+	for _, e := range d.Events {
+		if _, ok := eventMap[e.ID]; ok {
+			return nil, nil, errors.Wrapf(ErrMalformedDAG, "duplicate event %s", e.ID.String())
+		}
+		for _, parent := range e.Parents {
+			if _, ok := eventMap[parent]; !ok {
+				return nil, nil, errors.Wrapf(ErrMalformedDAG, "parent %s of event %s not found", parent.String(), e.ID.String())
+			}
+		}
+		event := &dbEvent{
+			hash:        e.ID,
+			validatorId: e.Creator,
+			seq:         e.Seq,
+			frame:       e.Frame,
+			lamportTs:   e.Lamport,
+			parents:     append(make([]consensus.EventHash, 0, len(e.Parents)), e.Parents...),
+		}
+		eventsOrdered = append(eventsOrdered, event)
+		eventMap[e.ID] = event
+	}
+	return eventsOrdered, eventMap, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochDAG_Replay(t *testing.T) {
+	assertar := assert.New(t)
+
+	conn := openTestEpochDB(t)
+	dag, err := ExportEpoch(conn, 76)
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Len(dag.Events, 7422)
+	assertar.Len(dag.Atropoi, 416)
+
+	var buf bytes.Buffer
+	assertar.NoError(dag.Write(&buf))
+	read, err := ReadEpochDAG(&buf)
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(dag, read)
+
+	// the replay matches the check against the DB, except of the skipped optional checks
+	expected := CheckEpoch(conn, 76)
+	expected.Skipped = nil
+	assertar.Equal(expected, ReplayEpoch(read))
+
+	// a divergence is found the same way
+	read.Events[len(read.Events)-1].Frame++
+	result := ReplayEpoch(read)
+	if assertar.NotNil(result.Divergence) {
+		assertar.Equal(DivergenceEvent, result.Divergence.Kind)
+		assertar.Equal(len(read.Events)-1, result.Divergence.Position)
+	}
+}
+
+func TestEpochDAG_Malformed(t *testing.T) {
+	assertar := assert.New(t)
+
+	_, err := ReadEpochDAG(strings.NewReader(`{"format": "lachesis-epoch-dag", "version": 2}`))
+	assertar.True(errors.Is(err, ErrUnknownDAGFormat))
+	_, err = ReadEpochDAG(strings.NewReader(`{"epoch": 1}`))
+	assertar.True(errors.Is(err, ErrUnknownDAGFormat))
+
+	conn := openTestEpochDB(t)
+	dag, err := ExportEpoch(conn, 76)
+	if !assertar.NoError(err) {
+		return
+	}
+	// a parent after its child
+	dag.Events[0], dag.Events[len(dag.Events)-1] = dag.Events[len(dag.Events)-1], dag.Events[0]
+	result := ReplayEpoch(dag)
+	assertar.True(errors.Is(result.Err(), ErrMalformedDAG))
+	if assertar.NotNil(result.Divergence) {
+		assertar.Equal(DivergenceError, result.Divergence.Kind)
+	}
+}
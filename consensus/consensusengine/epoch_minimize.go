@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+var ErrNoDivergence = errors.New("epoch DAG doesn't diverge")
+
+// MinimizeEpoch shrinks the DAG to a subgraph which still diverges with the same kind of divergence,
+// see consensustest.MinimizeDAG. The validators are kept along with their weights, so that the frames
+// of the remaining events stay valid expectations. Only the atropoi which remain in the DAG are expected,
+// and the lack of the further atropoi isn't considered as the divergence.
+// Returns the shrunk DAG along with its replay result.
+func MinimizeEpoch(dag *EpochDAG) (*EpochDAG, *EpochCheckResult, error) {
+	result := ReplayEpoch(dag)
+	if result.Divergence == nil {
+		return nil, nil, ErrNoDivergence
+	}
+	kind := result.Divergence.Kind
+	diverges := func(result *EpochCheckResult) bool {
+		d := result.Divergence
+		if d == nil || d.Kind != kind {
+			return false
+		}
+		// the missing atropoi are expected once the events are removed, unlike the wrong ones
+		return kind != DivergenceAtropos || d.Position < result.RecalculatedAtropoi
+	}
+	if !diverges(result) {
+		return nil, nil, errors.Wrapf(ErrNoDivergence, "only %d of %d atropoi recalculated", result.RecalculatedAtropoi, result.ExpectedAtropoi)
+	}
+
+	events := make(consensus.Events, 0, len(dag.Events))
+	for _, e := range dag.Events {
+		events = append(events, e.testEvent(dag.Epoch))
+	}
+	var minResult *EpochCheckResult
+	minimized := consensustest.MinimizeDAG(events, func(events consensus.Events) bool {
+		result := ReplayEpoch(dag.subDAG(events))
+		if diverges(result) {
+			minResult = result
+			return true
+		}
+		return false
+	})
+	if minResult == nil {
+		// the DAG can't be shrunk
+		return dag, result, nil
+	}
+	return dag.subDAG(minimized), minResult, nil
+}
+
+// ASCIIscheme draws the DAG by consensustest.DAGtoASCIIscheme.
+func (d *EpochDAG) ASCIIscheme() (string, error) {
+	events := make(consensus.Events, 0, len(d.Events))
+	for _, e := range d.Events {
+		events = append(events, e.testEvent(d.Epoch))
+	}
+	return consensustest.DAGtoASCIIscheme(events)
+}
+
+// subDAG returns the DAG of the events, with the atropoi which precede the first missing one
+func (d *EpochDAG) subDAG(events consensus.Events) *EpochDAG {
+	ids := events.IDs().Set()
+	sub := *d
+	sub.Events = make([]DAGEvent, 0, len(events))
+	for _, e := range d.Events {
+		if ids.Contains(e.ID) {
+			sub.Events = append(sub.Events, e)
+		}
+	}
+	sub.Atropoi = make(consensus.EventHashes, 0, len(d.Atropoi))
+	for _, atropos := range d.Atropoi {
+		if !ids.Contains(atropos) {
+			break
+		}
+		sub.Atropoi = append(sub.Atropoi, atropos)
+	}
+	return &sub
+}
+
+// testEvent converts the event, its ID is the same as the event's one
+func (e *DAGEvent) testEvent(epoch consensus.Epoch) *consensustest.TestEvent {
+	testEvent := &consensustest.TestEvent{}
+	testEvent.SetSeq(e.Seq)
+	testEvent.SetCreator(e.Creator)
+	testEvent.SetParents(e.Parents)
+	testEvent.SetLamport(e.Lamport)
+	testEvent.SetEpoch(epoch)
+	testEvent.SetFrame(e.Frame)
+	testEvent.SetID([24]byte(e.ID[8:]))
+	return testEvent
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimizeEpoch(t *testing.T) {
+	assertar := assert.New(t)
+
+	conn := openTestEpochDB(t)
+	dag, err := ExportEpoch(conn, 76)
+	if !assertar.NoError(err) {
+		return
+	}
+
+	_, _, err = MinimizeEpoch(dag)
+	assertar.True(errors.Is(err, ErrNoDivergence))
+
+	// the third atropos is expected to be a different root
+	dag.Atropoi[2] = dag.Atropoi[3]
+	minimized, result, err := MinimizeEpoch(dag)
+	if !assertar.NoError(err) {
+		return
+	}
+	if assertar.NotNil(result.Divergence) {
+		assertar.Equal(DivergenceAtropos, result.Divergence.Kind)
+		assertar.Equal(2, result.Divergence.Position)
+	}
+	assertar.Equal(result, ReplayEpoch(minimized))
+	assertar.Less(len(minimized.Events), len(dag.Events)/10)
+	assertar.Equal(dag.Validators, minimized.Validators)
+
+	// the minimized DAG is small enough for an ASCII scheme
+	for _, e := range minimized.Events {
+		assertar.Equal(e.ID, e.testEvent(minimized.Epoch).ID())
+	}
+	scheme, err := minimized.ASCIIscheme()
+	assertar.NoError(err)
+	assertar.NotEmpty(scheme)
+}
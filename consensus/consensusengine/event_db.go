@@ -0,0 +1,318 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+var (
+	ErrInvalidEventDBConfig = errors.New("invalid event DB config")
+	ErrEpochNotSealed       = errors.New("epoch isn't sealed")
+)
+
+// eventDBSchema is the schema of the event DB, as read by CheckEpoch, including the optional tables
+var eventDBSchema = []string{
+	`CREATE TABLE Event (
+		EventId INTEGER NOT NULL,
+		EventHash STRING NOT NULL,
+		FrameId INTEGER,
+		ValidatorId INTEGER,
+		EpochId INTEGER,
+		LamportNumber INTEGER,
+		SequenceNumber INTEGER,
+		CreationTime INTEGER,
+		MedianTime INTEGER,
+		PRIMARY KEY (EventHash)
+	)`,
+	`CREATE UNIQUE INDEX EventIndex ON Event(EventId)`,
+	`CREATE TABLE Parent (
+		EventId INTEGER NOT NULL,
+		ParentId INTEGER NOT NULL,
+		PRIMARY KEY (EventId, ParentId)
+	)`,
+	`CREATE TABLE Validator (
+		EpochId INTEGER NOT NULL,
+		ValidatorId INTEGER NOT NULL,
+		Weight INTEGER NOT NULL,
+		PRIMARY KEY (EpochId, ValidatorId)
+	)`,
+	`CREATE TABLE Atropos (
+		AtroposId INTEGER NOT NULL UNIQUE,
+		PRIMARY KEY (AtroposId)
+	)`,
+	`CREATE TABLE ` + CheatersTable + ` (
+		AtroposId INTEGER NOT NULL,
+		ValidatorId INTEGER NOT NULL,
+		PRIMARY KEY (AtroposId, ValidatorId)
+	)`,
+	`CREATE TABLE ` + ConfirmedTable + ` (
+		AtroposId INTEGER NOT NULL,
+		EventId INTEGER NOT NULL,
+		PRIMARY KEY (AtroposId, EventId)
+	)`,
+	`CREATE TABLE ` + RootsTable + ` (
+		EventId INTEGER NOT NULL,
+		PRIMARY KEY (EventId)
+	)`,
+}
+
+// EventDBConfig is the configuration of a random event DB generated by GenerateEventDB.
+type EventDBConfig struct {
+	// Seed of the generation, the same config generates the same DB
+	Seed int64
+	// Epochs is the number of the generated epochs, starting from consensus.FirstEpoch
+	Epochs int
+	// Validators is the number of validators in each epoch
+	Validators int
+	// Cheaters is the number of validators creating forks in each epoch, they are the validators with the lowest IDs
+	Cheaters int
+	// Forks is the max number of forks of a cheater in an epoch
+	Forks int
+	// Parents is the max number of parents of an event
+	Parents int
+	// EpochBlocks is the number of blocks after which an epoch is sealed
+	EpochBlocks int
+	// EpochEvents is the max number of events of a validator in an epoch,
+	// the epoch fails to generate if it isn't sealed before that
+	EpochEvents int
+}
+
+// DefaultEventDBConfig generates a few small epochs.
+func DefaultEventDBConfig() EventDBConfig {
+	return EventDBConfig{
+		Epochs:      3,
+		Validators:  7,
+		Cheaters:    1,
+		Forks:       5,
+		Parents:     4,
+		EpochBlocks: 10,
+		EpochEvents: 200,
+	}
+}
+
+// Validate checks that the config describes a DB which can be generated.
+func (c EventDBConfig) Validate() error {
+	switch {
+	case c.Epochs <= 0:
+		return errors.Wrapf(ErrInvalidEventDBConfig, "%d epochs", c.Epochs)
+	case c.Validators <= 0:
+		return errors.Wrapf(ErrInvalidEventDBConfig, "%d validators", c.Validators)
+	case c.Cheaters < 0 || c.Cheaters*3 >= c.Validators:
+		return errors.Wrapf(ErrInvalidEventDBConfig, "%d cheaters of %d validators", c.Cheaters, c.Validators)
+	case c.Parents <= 0:
+		return errors.Wrapf(ErrInvalidEventDBConfig, "%d parents", c.Parents)
+	case c.EpochBlocks <= 0 || c.EpochEvents <= 0:
+		return errors.Wrapf(ErrInvalidEventDBConfig, "%d blocks of %d events per epoch", c.EpochBlocks, c.EpochEvents)
+	}
+	return nil
+}
+
+// genEpoch is a generated epoch
+type genEpoch struct {
+	epoch      consensus.Epoch
+	validators *consensus.Validators
+	// events are in the order of processing
+	events consensus.Events
+	roots  consensus.EventHashSet
+	blocks []*genBlock
+}
+
+// genBlock is a block decided in a generated epoch
+type genBlock struct {
+	atropos   consensus.EventHash
+	cheaters  consensus.Cheaters
+	confirmed consensus.EventHashes
+}
+
+// GenerateEventDB generates random epochs by consensustest.ForEachRandFork, processes them by CoreLachesis,
+// and writes them into the empty event DB, along with the optional tables checked by CheckEpoch.
+// Every epoch is sealed after config.EpochBlocks blocks; the events built after the sealing are dropped.
+// Between the epochs the weights of the validators change, and one of the validators is replaced by a new one.
+// The Validator table also holds the validators of the epoch following the last generated one.
+func GenerateEventDB(conn *sql.DB, config EventDBConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	epochs, next, err := generateEpochs(config)
+	if err != nil {
+		return err
+	}
+	return writeEventDB(conn, epochs, next)
+}
+
+// generateEpochs generates the epochs, returns them along with the validators of the next epoch
+func generateEpochs(config EventDBConfig) ([]*genEpoch, *consensus.Validators, error) {
+	r := rand.New(rand.NewSource(config.Seed)) // nolint:gosec
+	// the validator IDs are sequential rather than consensustest.GenNodes, to be reproducible by the seed
+	nodes := make([]consensus.ValidatorID, config.Validators)
+	weights := make([]consensus.Weight, config.Validators)
+	for i := range nodes {
+		nodes[i] = consensus.ValidatorID(i + 1)
+		weights[i] = randWeight(r)
+	}
+	nextID := consensus.ValidatorID(len(nodes) + 1)
+	lch, store, input, _ := NewCoreLachesis(nodes, weights)
+
+	var (
+		current *genEpoch
+		err     error
+	)
+	lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		confirmed, confirmedErr := store.GetEventsConfirmedBy(block.Atropos)
+		if confirmedErr != nil {
+			err = confirmedErr
+		}
+		current.blocks = append(current.blocks, &genBlock{
+			atropos:   block.Atropos,
+			cheaters:  block.Cheaters,
+			confirmed: confirmed,
+		})
+		if len(current.blocks) == config.EpochBlocks {
+			return nextValidators(r, store.GetValidators(), &nextID)
+		}
+		return nil
+	}
+
+	epochs := make([]*genEpoch, 0, config.Epochs)
+	for epoch := consensus.FirstEpoch; len(epochs) < config.Epochs; epoch++ {
+		current = &genEpoch{
+			epoch:      epoch,
+			validators: store.GetValidators(),
+			roots:      consensus.EventHashSet{},
+		}
+		epochs = append(epochs, current)
+		frames := map[consensus.EventHash]consensus.Frame{}
+		nodes := current.validators.SortedIDs()
+		consensustest.ForEachRandFork(nodes, nodes[:config.Cheaters], config.EpochEvents, min(config.Parents, len(nodes)), config.Forks, r, consensustest.ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				input.SetEvent(e)
+				frames[e.ID()] = e.Frame()
+				if sp := e.SelfParent(); sp == nil || frames[*sp] != e.Frame() {
+					current.roots.Add(e.ID())
+				}
+				current.events = append(current.events, e)
+				if processErr := lch.Process(e); processErr != nil {
+					err = errors.Wrapf(processErr, "event %s of epoch %d", name, epoch)
+				}
+			},
+			Build: func(e consensus.MutableEvent, name string) error {
+				if err != nil {
+					return err
+				}
+				if epoch != store.GetEpoch() {
+					return errors.New("epoch already sealed, skip")
+				}
+				e.SetEpoch(epoch)
+				return lch.Build(e)
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if epoch == store.GetEpoch() {
+			return nil, nil, errors.Wrapf(ErrEpochNotSealed, "epoch %d decided %d of %d blocks by %d events per validator",
+				epoch, len(current.blocks), config.EpochBlocks, config.EpochEvents)
+		}
+	}
+	return epochs, store.GetValidators(), nil
+}
+
+// randWeight returns a random weight, the weights of the validators differ at most twice
+func randWeight(r *rand.Rand) consensus.Weight {
+	return consensus.Weight(50 + r.Intn(51))
+}
+
+// nextValidators changes the weights of the validators, and replaces a random validator with a new one
+func nextValidators(r *rand.Rand, validators *consensus.Validators, nextID *consensus.ValidatorID) *consensus.Validators {
+	ids := validators.SortedIDs()
+	replaced := ids[r.Intn(len(ids))]
+	builder := consensus.NewBuilder()
+	for _, id := range ids {
+		if id != replaced {
+			builder.Set(id, randWeight(r))
+		}
+	}
+	builder.Set(*nextID, randWeight(r))
+	*nextID++
+	return builder.Build()
+}
+
+// writeEventDB writes the epochs in a single transaction.
+// The event IDs are ordered by epoch and frame, so that the atropoi are ordered by ID in the order of the blocks.
+func writeEventDB(conn *sql.DB, epochs []*genEpoch, next *consensus.Validators) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+	exec := func(query string, args ...any) {
+		if err != nil {
+			return
+		}
+		if _, execErr := tx.Exec(query, args...); execErr != nil {
+			err = fmt.Errorf("failed to write the event DB: %w", execErr)
+		}
+	}
+	for _, query := range eventDBSchema {
+		exec(query)
+	}
+
+	writeValidators := func(epoch consensus.Epoch, validators *consensus.Validators) {
+		for _, id := range validators.SortedIDs() {
+			exec(`INSERT INTO Validator (EpochId, ValidatorId, Weight) VALUES (?, ?, ?)`, epoch, id, validators.Get(id))
+		}
+	}
+	ids := map[consensus.EventHash]int64{}
+	for _, epoch := range epochs {
+		writeValidators(epoch.epoch, epoch.validators)
+
+		events := append(consensus.Events{}, epoch.events...)
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].Frame() < events[j].Frame()
+		})
+		for _, e := range events {
+			ids[e.ID()] = int64(len(ids) + 1)
+			exec(`INSERT INTO Event (EventId, EventHash, FrameId, ValidatorId, EpochId, LamportNumber, SequenceNumber) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				ids[e.ID()], e.ID().Hex(), e.Frame(), e.Creator(), e.Epoch(), e.Lamport(), e.Seq())
+			if epoch.roots.Contains(e.ID()) {
+				exec(`INSERT INTO `+RootsTable+` (EventId) VALUES (?)`, ids[e.ID()])
+			}
+		}
+		for _, e := range events {
+			for _, p := range e.Parents() {
+				exec(`INSERT INTO Parent (EventId, ParentId) VALUES (?, ?)`, ids[e.ID()], ids[p])
+			}
+		}
+		for _, block := range epoch.blocks {
+			exec(`INSERT INTO Atropos (AtroposId) VALUES (?)`, ids[block.atropos])
+			for _, cheater := range block.cheaters {
+				exec(`INSERT INTO `+CheatersTable+` (AtroposId, ValidatorId) VALUES (?, ?)`, ids[block.atropos], cheater)
+			}
+			for _, e := range block.confirmed {
+				exec(`INSERT INTO `+ConfirmedTable+` (AtroposId, EventId) VALUES (?, ?)`, ids[block.atropos], ids[e])
+			}
+		}
+	}
+	writeValidators(epochs[len(epochs)-1].epoch+1, next)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
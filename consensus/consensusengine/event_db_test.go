@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+func TestGenerateEventDB(t *testing.T) {
+	assertar := assert.New(t)
+
+	config := DefaultEventDBConfig()
+	config.Seed = 1
+	conn := openEmptyEventDB(t)
+	if !assertar.NoError(GenerateEventDB(conn, config)) {
+		return
+	}
+
+	epochMin, epochMax, err := GetEpochRange(conn)
+	assertar.NoError(err)
+	assertar.Equal(consensus.FirstEpoch, epochMin)
+	assertar.Equal(consensus.Epoch(config.Epochs), epochMax)
+
+	prev := map[consensus.ValidatorID]bool{}
+	for epoch := epochMin; epoch <= epochMax+1; epoch++ {
+		validators, _, err := getValidator(conn, epoch)
+		assertar.NoError(err)
+		assertar.Len(validators, config.Validators)
+		if epoch != epochMin {
+			// a validator is replaced
+			changed := 0
+			for _, v := range validators {
+				if !prev[v] {
+					changed++
+				}
+			}
+			assertar.Equal(1, changed, "epoch %d", epoch)
+		}
+		prev = map[consensus.ValidatorID]bool{}
+		for _, v := range validators {
+			prev[v] = true
+		}
+		if epoch > epochMax {
+			break
+		}
+
+		result := CheckEpoch(conn, epoch)
+		assertar.NoError(result.Err())
+		assertar.Empty(result.Skipped)
+		assertar.Equal(config.EpochBlocks, result.ExpectedAtropoi)
+		assertar.GreaterOrEqual(result.RecalculatedAtropoi, result.ExpectedAtropoi)
+	}
+
+	// the cheaters are detected
+	var cheaters int
+	assertar.NoError(conn.QueryRow(`SELECT COUNT(DISTINCT ValidatorId) FROM ` + CheatersTable).Scan(&cheaters))
+	assertar.NotZero(cheaters)
+
+	// the DB is reproducible by the seed
+	again := openEmptyEventDB(t)
+	assertar.NoError(GenerateEventDB(again, config))
+	for _, query := range []string{
+		`SELECT COUNT(*), SUM(EventId * LamportNumber) FROM Event`,
+		`SELECT COUNT(*), SUM(AtroposId) FROM Atropos`,
+		`SELECT COUNT(*), SUM(ValidatorId * Weight) FROM Validator`,
+	} {
+		var count, sum, countAgain, sumAgain int64
+		assertar.NoError(conn.QueryRow(query).Scan(&count, &sum))
+		assertar.NoError(again.QueryRow(query).Scan(&countAgain, &sumAgain))
+		assertar.Equal(count, countAgain, query)
+		assertar.Equal(sum, sumAgain, query)
+	}
+}
+
+func TestGenerateEventDB_Errors(t *testing.T) {
+	assertar := assert.New(t)
+
+	config := DefaultEventDBConfig()
+	config.Cheaters = config.Validators
+	assertar.True(errors.Is(GenerateEventDB(openEmptyEventDB(t), config), ErrInvalidEventDBConfig))
+
+	config = DefaultEventDBConfig()
+	config.EpochEvents = 5
+	assertar.True(errors.Is(GenerateEventDB(openEmptyEventDB(t), config), ErrEpochNotSealed))
+}
+
+// openEmptyEventDB opens a new sqlite DB in a temporary dir
+func openEmptyEventDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
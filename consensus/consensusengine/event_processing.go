@@ -17,7 +17,9 @@ import (
 )
 
 var (
-	ErrWrongFrame = errors.New("claimed frame mismatched with calculated")
+	ErrWrongFrame       = errors.New("claimed frame mismatched with calculated")
+	ErrWrongEpoch       = errors.New("event has wrong epoch")
+	ErrUnknownValidator = errors.New("event wasn't created by an existing validator")
 )
 
 // Build fills consensus-related fields: Frame, IsRoot
@@ -25,13 +27,16 @@ var (
 func (p *Orderer) Build(e consensus.MutableEvent) error {
 	// sanity check
 	if e.Epoch() != p.store.GetEpoch() {
-		p.crit(errors.New("event has wrong epoch"))
+		return ErrWrongEpoch
 	}
 	if !p.store.GetValidators().Exists(e.Creator()) {
-		p.crit(errors.New("event wasn't created by an existing validator"))
+		return ErrUnknownValidator
 	}
 
-	_, frame := p.calcFrameIdx(e)
+	_, frame, err := p.calcFrameIdx(e)
+	if err != nil {
+		return p.fail(err)
+	}
 	e.SetFrame(frame)
 
 	return nil
@@ -44,46 +49,54 @@ func (p *Orderer) Build(e consensus.MutableEvent) error {
 func (p *Orderer) Process(e consensus.Event) (err error) {
 	err, selfParentFrame := p.checkAndSaveEvent(e)
 	if err != nil {
-		return err
+		return p.fail(err)
 	}
+	p.metrics.eventsProcessed.Inc(1)
 
 	if selfParentFrame == e.Frame() {
 		return nil
 	}
-	if err := p.handleElection(e); err != nil {
-		// election doesn't fail under normal circumstances
-		// storage is in an inconsistent state
-		p.crit(err)
-	}
-	return err
+	// election doesn't fail under normal circumstances,
+	// an error means that storage is in an inconsistent state
+	return p.fail(p.handleElection(e))
 }
 
 // Process event that's been built locally
 func (p *Orderer) ProcessLocalEvent(e consensus.Event) (err error) {
-	selfParentFrame := p.getSelfParentFrame(e)
+	selfParentFrame, err := p.getSelfParentFrame(e)
+	if err != nil {
+		return p.fail(err)
+	}
+	p.metrics.eventsProcessed.Inc(1)
 	if selfParentFrame == e.Frame() {
 		return nil
 	}
 	// It's a root
-	p.store.AddRoot(e)
-	if err := p.handleElection(e); err != nil {
-		// election doesn't fail under normal circumstances
-		// storage is in an inconsistent state
-		p.crit(err)
+	if err := p.store.AddRoot(e); err != nil {
+		return p.fail(err)
 	}
-	return err
+	p.metrics.onRoot(e.Frame())
+	// election doesn't fail under normal circumstances,
+	// an error means that storage is in an inconsistent state
+	return p.fail(p.handleElection(e))
 }
 
 // checkAndSaveEvent checks consensus-related fields: Frame, IsRoot
 func (p *Orderer) checkAndSaveEvent(e consensus.Event) (error, consensus.Frame) {
 	// check frame & isRoot
-	selfParentFrame, frameIdx := p.calcFrameIdx(e)
+	selfParentFrame, frameIdx, err := p.calcFrameIdx(e)
+	if err != nil {
+		return err, 0
+	}
 	if !p.config.SuppressFramePanic && e.Frame() != frameIdx {
 		return ErrWrongFrame, 0
 	}
 
 	if selfParentFrame != frameIdx {
-		p.store.AddRoot(e)
+		if err := p.store.AddRoot(e); err != nil {
+			return err, 0
+		}
+		p.metrics.onRoot(e.Frame())
 	}
 	return nil, selfParentFrame
 }
@@ -94,78 +107,170 @@ func (p *Orderer) handleElection(root consensus.Event) error {
 	if err != nil {
 		return err
 	}
+	if el, ok := p.election.(*election); ok {
+		p.metrics.electionMatrixSize.Update(int64(el.voteMatrixSize))
+	}
+	sealed, err := p.onFramesDecided(decisions)
+	if err != nil || sealed || len(decisions) == 0 {
+		return err
+	}
+	return p.checkpointElection()
+}
+
+// onFramesDecided calls p.onFrameDecided for each of the decisions, until the epoch is sealed
+func (p *Orderer) onFramesDecided(decisions []*AtroposDecision) (sealed bool, err error) {
 	for _, atroposDecision := range decisions {
 		sealed, err := p.onFrameDecided(atroposDecision.Frame, atroposDecision.AtroposHash)
-		if err != nil {
-			return err
-		}
-		if sealed {
-			return nil
+		if err != nil || sealed {
+			return sealed, err
 		}
 	}
-	return nil
+	return false, nil
+}
+
+// checkpointElection persists the election vote state, so that Bootstrap doesn't need to re-vote the roots
+func (p *Orderer) checkpointElection() error {
+	checkpointer, ok := p.election.(ElectionCheckpointer)
+	if !ok {
+		return nil
+	}
+	state, err := checkpointer.Checkpoint()
+	if err != nil {
+		return err
+	}
+	return p.store.SetElectionState(state)
+}
+
+// restoreElection loads the election vote state from the last checkpoint.
+// Returns the roots which are already voted, or nil if there's no usable checkpoint.
+func (p *Orderer) restoreElection() (map[consensus.EventHash]bool, error) {
+	checkpointer, ok := p.election.(ElectionCheckpointer)
+	if !ok {
+		return nil, nil
+	}
+	state, err := p.store.GetElectionState()
+	if err != nil || state == nil {
+		return nil, err
+	}
+	// checkpoint is stale if the last decided frame was persisted after it
+	if state.FrameToDeliver != p.store.GetLastDecidedFrame()+1 {
+		return nil, nil
+	}
+	checkpointer.Restore(state)
+
+	voted := make(map[consensus.EventHash]bool, len(state.Votes)+len(state.VotedRoots))
+	for _, v := range state.Votes {
+		voted[v.RootHash] = true
+	}
+	for _, root := range state.VotedRoots {
+		voted[root] = true
+	}
+	return voted, nil
 }
 
 func (p *Orderer) bootstrapElection() error {
+	voted, err := p.restoreElection()
+	if err != nil {
+		return err
+	}
+	decided := false
 	for frame := p.store.GetLastDecidedFrame() + 1; ; frame++ {
-		frameRoots := p.store.GetFrameRoots(frame)
+		frameRoots, err := p.store.GetFrameRoots(frame)
+		if err != nil {
+			return err
+		}
 		if len(frameRoots) == 0 {
 			break
 		}
 		for _, root := range frameRoots {
+			if voted[root.RootHash] {
+				continue
+			}
 			decisions, err := p.election.VoteAndAggregate(frame, root.ValidatorID, root.RootHash)
 			if err != nil {
 				return err
 			}
-			for _, atroposDecision := range decisions {
-				sealed, err := p.onFrameDecided(atroposDecision.Frame, atroposDecision.AtroposHash)
-				if err != nil {
-					return err
-				}
-				if sealed {
-					return nil
-				}
+			sealed, err := p.onFramesDecided(decisions)
+			if err != nil || sealed {
+				return err
 			}
+			decided = decided || len(decisions) != 0
 		}
 	}
-	return nil
+	if !decided {
+		return nil
+	}
+	return p.checkpointElection()
 }
 
-// forklessCausedByQuorumOn returns true if event is forkless caused by 2/3W roots on specified frame
-func (p *Orderer) forklessCausedByQuorumOn(e consensus.Event, f consensus.Frame) bool {
+// forklessCausedByQuorumOn returns true if event is forkless caused by the quorum of roots on specified frame
+func (p *Orderer) forklessCausedByQuorumOn(e consensus.Event, f consensus.Frame) (bool, error) {
+	observedCounter, err := p.forklessCausedCounterOn(e, f, true)
+	if err != nil {
+		return false, err
+	}
+	return observedCounter.HasQuorum(), nil
+}
+
+// forklessCausedCounterOn counts the validators whose roots on specified frame are forkless caused by event.
+// If untilQuorum is true, the counting stops once the quorum is reached.
+func (p *Orderer) forklessCausedCounterOn(e consensus.Event, f consensus.Frame, untilQuorum bool) (*consensus.WeightCounter, error) {
 	observedCounter := p.store.GetValidators().NewCounter()
+	frameRoots, err := p.store.GetFrameRoots(f)
+	if err != nil {
+		return nil, err
+	}
 	// check "observing" prev roots only if called by creator, or if creator has marked that event as root
-	for _, it := range p.store.GetFrameRoots(f) {
-		if p.dagIndex.ForklessCause(e.ID(), it.RootHash) {
+	for _, it := range frameRoots {
+		forklessCaused, err := p.dagIndex.ForklessCause(e.ID(), it.RootHash)
+		if err != nil {
+			return nil, err
+		}
+		if forklessCaused {
 			observedCounter.Count(it.ValidatorID)
 		}
-		if observedCounter.HasQuorum() {
+		if untilQuorum && observedCounter.HasQuorum() {
 			break
 		}
 	}
-	return observedCounter.HasQuorum()
+	return observedCounter, nil
 }
 
 // calcFrameIdx is not safe for concurrent use.
-func (p *Orderer) calcFrameIdx(e consensus.Event) (selfParentFrame, frame consensus.Frame) {
+func (p *Orderer) calcFrameIdx(e consensus.Event) (selfParentFrame, frame consensus.Frame, err error) {
 	if e.SelfParent() == nil {
-		return 0, 1
+		return 0, 1, nil
+	}
+	selfParentFrame, err = p.getSelfParentFrame(e)
+	if err != nil {
+		return 0, 0, err
 	}
-	selfParentFrame = p.Input.GetEvent(*e.SelfParent()).Frame()
 	frame = selfParentFrame
 	for _, parent := range e.Parents() {
-		frame = max(frame, p.Input.GetEvent(parent).Frame())
+		parentEvent := p.Input.GetEvent(parent)
+		if parentEvent == nil {
+			return 0, 0, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "parent %s of event %s not found", parent.String(), e.ID().String())
+		}
+		frame = max(frame, parentEvent.Frame())
 	}
 
-	if p.forklessCausedByQuorumOn(e, frame) {
+	forklessCaused, err := p.forklessCausedByQuorumOn(e, frame)
+	if err != nil {
+		return 0, 0, err
+	}
+	if forklessCaused {
 		frame++
 	}
-	return selfParentFrame, frame
+	return selfParentFrame, frame, nil
 }
 
-func (p *Orderer) getSelfParentFrame(e consensus.Event) consensus.Frame {
+func (p *Orderer) getSelfParentFrame(e consensus.Event) (consensus.Frame, error) {
 	if e.SelfParent() == nil {
-		return 0
+		return 0, nil
+	}
+	selfParent := p.Input.GetEvent(*e.SelfParent())
+	if selfParent == nil {
+		return 0, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "self-parent %s of event %s not found", e.SelfParent().String(), e.ID().String())
 	}
-	return p.Input.GetEvent(*e.SelfParent()).Frame()
+	return selfParent.Frame(), nil
 }
@@ -43,6 +43,14 @@ func TestLachesisRandom_big3(t *testing.T) {
 	testLachesisRandom(t, []consensus.Weight{math.MaxUint32 / 8, math.MaxUint32 / 8, math.MaxUint32 / 4}, 0)
 }
 
+func TestLachesisRandom_huge2(t *testing.T) {
+	testLachesisRandom(t, []consensus.Weight{consensus.MaxTotalWeight / 2, consensus.MaxTotalWeight / 2}, 0)
+}
+
+func TestLachesisRandom_huge3(t *testing.T) {
+	testLachesisRandom(t, []consensus.Weight{math.MaxUint32 + 1, consensus.MaxTotalWeight / 4, 1}, 0)
+}
+
 func TestLachesisRandom_4(t *testing.T) {
 	testLachesisRandom(t, []consensus.Weight{1, 2, 3, 4}, 0)
 }
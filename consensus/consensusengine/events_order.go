@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"bytes"
+	"container/heap"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// EventLessFn reports whether event a must be delivered before event b, unless it's prohibited by parents order.
+type EventLessFn func(a, b consensus.Event) bool
+
+// ByLamportCreatorHash orders events by Lamport time, then by creator, then by hash.
+// Parents always have lower Lamport time than their children, so the order is topological by itself.
+func ByLamportCreatorHash(a, b consensus.Event) bool {
+	if a.Lamport() != b.Lamport() {
+		return a.Lamport() < b.Lamport()
+	}
+	if a.Creator() != b.Creator() {
+		return a.Creator() < b.Creator()
+	}
+	return bytes.Compare(a.ID().Bytes(), b.ID().Bytes()) < 0
+}
+
+// eventsHeap is a min-heap of events ordered by the less function.
+type eventsHeap struct {
+	container consensus.Events
+	less      EventLessFn
+}
+
+func (h eventsHeap) Len() int           { return len(h.container) }
+func (h eventsHeap) Less(i, j int) bool { return h.less(h.container[i], h.container[j]) }
+func (h eventsHeap) Swap(i, j int)      { h.container[i], h.container[j] = h.container[j], h.container[i] }
+
+func (h *eventsHeap) Push(x any) {
+	h.container = append(h.container, x.(consensus.Event))
+}
+
+func (h *eventsHeap) Pop() any {
+	backIdx := len(h.container) - 1
+	toPop := h.container[backIdx]
+	h.container = h.container[0:backIdx]
+	return toPop
+}
+
+// orderEvents sorts events topologically: parents are always before their children,
+// otherwise events are ordered by the less function.
+// Parents which are not in the events set are ignored.
+func orderEvents(events consensus.Events, less EventLessFn) consensus.Events {
+	indices := make(map[consensus.EventHash]int, len(events))
+	for i, e := range events {
+		indices[e.ID()] = i
+	}
+	// number of the event's parents which aren't delivered yet
+	pending := make([]int, len(events))
+	children := make([][]int, len(events))
+	for i, e := range events {
+		for _, parent := range e.Parents() {
+			if parentIdx, ok := indices[parent]; ok {
+				pending[i]++
+				children[parentIdx] = append(children[parentIdx], i)
+			}
+		}
+	}
+
+	ready := &eventsHeap{less: less}
+	for i, e := range events {
+		if pending[i] == 0 {
+			ready.container = append(ready.container, e)
+		}
+	}
+	heap.Init(ready)
+
+	ordered := make(consensus.Events, 0, len(events))
+	for ready.Len() > 0 {
+		e := heap.Pop(ready).(consensus.Event)
+		ordered = append(ordered, e)
+		for _, childIdx := range children[indices[e.ID()]] {
+			pending[childIdx]--
+			if pending[childIdx] == 0 {
+				heap.Push(ready, events[childIdx])
+			}
+		}
+	}
+	return ordered
+}
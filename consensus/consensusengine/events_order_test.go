@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestOrderEvents(t *testing.T) {
+	_, _, named := consensustest.ASCIIschemeToDAG(`
+a00 b00   c00 d00
+║   ║     ║   ║
+a01 ║     ║   ║
+║   ╠  ─  c01 ║
+a02 ╣     ║   ║
+║   ║     ║   ║
+╠ ─ ╫ ─ ─ c02 ║
+║   b01  ╝║   ║
+║   ╠ ─ ─ ╫ ─ d01
+║   ║     ║   ║
+╠ ═ b02═══╬   ╣
+`)
+	events := make(consensus.Events, 0, len(named))
+	for _, e := range named {
+		events = append(events, e)
+	}
+
+	t.Run("ByLamportCreatorHash", func(t *testing.T) {
+		ordered := orderEvents(events, ByLamportCreatorHash)
+		assert.Len(t, ordered, len(events))
+		for i := 1; i < len(ordered); i++ {
+			assert.True(t, ByLamportCreatorHash(ordered[i-1], ordered[i]))
+		}
+		assertParentsFirst(t, ordered)
+	})
+
+	t.Run("ParentsFirst", func(t *testing.T) {
+		// prefers descendants, so that only the parents order restricts it
+		reversed := func(a, b consensus.Event) bool {
+			return ByLamportCreatorHash(b, a)
+		}
+		ordered := orderEvents(events, reversed)
+		assert.Len(t, ordered, len(events))
+		assertParentsFirst(t, ordered)
+		// the comparator is respected whenever the parents allow it
+		assert.NotEqual(t, orderEvents(events, ByLamportCreatorHash), ordered)
+		assert.Empty(t, ordered[0].Parents())
+	})
+}
+
+func TestConfirmEvents_Ordered(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+	lch.config.ConfirmedEventsOrder = ByLamportCreatorHash
+
+	var frames []consensus.Frame
+	var blocks []*consensus.Block
+	lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		frames = append(frames, lch.store.GetLastDecidedFrame()+1)
+		blocks = append(blocks, block)
+		return nil
+	}
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:1], TestMaxEpochEvents, 3, 10, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if !assertar.NotEmpty(blocks) {
+		return
+	}
+
+	// unconfirm all events
+	it := lch.store.EpochTable.ConfirmedEvent.NewIterator(nil, nil)
+	batch := lch.store.EpochTable.ConfirmedEvent.NewBatch()
+	for it.Next() {
+		assertar.NoError(batch.Delete(it.Key()))
+	}
+	assertar.NoError(batch.Write())
+	it.Release()
+
+	var applied consensus.Events
+	for i, block := range blocks {
+		var blockEvents consensus.Events
+		err := lch.confirmEvents(frames[i], block.Atropos, func(e consensus.Event) {
+			blockEvents = append(blockEvents, e)
+		})
+		if !assertar.NoError(err) {
+			return
+		}
+		for j := 1; j < len(blockEvents); j++ {
+			assertar.True(ByLamportCreatorHash(blockEvents[j-1], blockEvents[j]))
+		}
+		// positions follow the delivery order
+		confirmed, err := lch.store.GetEventsConfirmedBy(block.Atropos)
+		assertar.NoError(err)
+		assertar.Equal(len(blockEvents), len(confirmed))
+		for j, e := range blockEvents {
+			assertar.Equal(e.ID(), confirmed[j])
+		}
+		applied = append(applied, blockEvents...)
+	}
+	assertParentsFirst(t, applied)
+}
+
+// assertParentsFirst checks that every parent in the list goes before its child
+func assertParentsFirst(t *testing.T, events consensus.Events) {
+	t.Helper()
+	positions := make(map[consensus.EventHash]int, len(events))
+	for i, e := range events {
+		assert.NotContains(t, positions, e.ID(), "event is delivered twice")
+		positions[e.ID()] = i
+	}
+	for i, e := range events {
+		for _, parent := range e.Parents() {
+			if pos, ok := positions[parent]; ok {
+				assert.Less(t, pos, i, "parent is delivered after the child")
+			}
+		}
+	}
+}
@@ -17,10 +17,24 @@ import (
 // onFrameDecided moves LastDecidedFrameN to frame.
 // It includes: moving current decided frame, txs ordering and execution, epoch sealing.
 func (p *Orderer) onFrameDecided(frame consensus.Frame, atropos consensus.EventHash) (bool, error) {
+	rootsNum := 0
+	if p.metrics.enabled {
+		frameRoots, err := p.store.GetFrameRoots(frame)
+		if err != nil {
+			return false, err
+		}
+		rootsNum = len(frameRoots)
+	}
+	p.metrics.onFrameDecided(frame, rootsNum)
+
 	// new checkpoint
 	var newValidators *consensus.Validators
 	if p.callback.ApplyAtropos != nil {
-		newValidators = p.callback.ApplyAtropos(frame, atropos)
+		var err error
+		newValidators, err = p.callback.ApplyAtropos(frame, atropos)
+		if err != nil {
+			return false, err
+		}
 	}
 
 	lastDecidedState := *p.store.GetLastDecidedState()
@@ -30,11 +44,13 @@ func (p *Orderer) onFrameDecided(frame consensus.Frame, atropos consensus.EventH
 		if err != nil {
 			return true, err
 		}
-		p.election.ResetEpoch(consensus.FirstFrame, newValidators)
+		p.election.ResetEpoch(consensus.FirstFrame, p.store.GetValidators())
 	} else {
 		lastDecidedState.LastDecidedFrame = frame
 	}
-	p.store.SetLastDecidedState(&lastDecidedState)
+	if err := p.store.SetLastDecidedState(&lastDecidedState); err != nil {
+		return newValidators != nil, err
+	}
 	return newValidators != nil, nil
 }
 
@@ -59,7 +75,10 @@ func (p *Orderer) sealEpoch(newValidators *consensus.Validators) error {
 	epochState := *p.store.GetEpochState()
 	epochState.Epoch++
 	epochState.Validators = newValidators
-	p.store.SetEpochState(&epochState)
+	if err := p.store.SetEpochState(&epochState); err != nil {
+		return err
+	}
+	p.metrics.onEpochSwitched()
 
 	return p.resetEpochStore(epochState.Epoch)
 }
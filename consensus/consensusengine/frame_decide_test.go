@@ -11,6 +11,7 @@
 package consensusengine
 
 import (
+	"errors"
 	"math"
 	"math/rand"
 	"testing"
@@ -18,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
 	"github.com/0xsoniclabs/consensus/consensus/consensustest"
 )
 
@@ -130,3 +132,149 @@ func testConfirmBlocks(t *testing.T, weights []consensus.Weight, cheatersCount i
 	}
 	assertar.GreaterOrEqual(len(blocks), TestMaxEpochEvents/5)
 }
+
+func TestConfirmedEventsQuery(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+
+	var processed consensus.Events
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:1], TestMaxEpochEvents, 3, 10, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+			processed = append(processed, e)
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if !assertar.NotEmpty(lch.blocks) {
+		return
+	}
+
+	confirmed := 0
+	for key, block := range lch.blocks {
+		events, err := lch.store.GetEventsConfirmedBy(block.Atropos)
+		if !assertar.NoError(err) || !assertar.NotEmpty(events) {
+			return
+		}
+		// Atropos is the first event of its block
+		assertar.Equal(block.Atropos, events[0])
+		for position, id := range events {
+			c, err := lch.store.GetEventConfirmation(id)
+			assertar.NoError(err)
+			assertar.Equal(&consensusstore.EventConfirmation{Frame: key.Frame, Atropos: block.Atropos, Position: uint32(position)}, c)
+		}
+		confirmed += len(events)
+		if len(events) > 1 {
+			// confirmed event which isn't an Atropos
+			notAtropos, err := lch.store.GetEventsConfirmedBy(events[1])
+			assertar.NoError(err)
+			assertar.Nil(notAtropos)
+		}
+	}
+
+	// every confirmed event is listed in its block
+	for _, e := range processed {
+		c, err := lch.store.GetEventConfirmation(e.ID())
+		assertar.NoError(err)
+		if c != nil {
+			confirmed--
+		}
+	}
+	assertar.Equal(0, confirmed)
+}
+
+func TestBlockLog(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	config := LiteConfig()
+	config.BlockLog = true
+	config.BlockLogEpochs = 2
+	lch, _, input, _ := newCoreLachesis(nodes, nil, config)
+
+	const epochs = 4
+	maxEpochBlocks := TestMaxEpochEvents / 20
+	lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		if lch.store.GetLastDecidedFrame()+1 == consensus.Frame(maxEpochBlocks) {
+			return mutateValidators(lch.store.GetValidators())
+		}
+		return nil
+	}
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	for epoch := consensus.Epoch(1); epoch <= epochs; epoch++ {
+		consensustest.ForEachRandFork(nodes, nodes[:1], TestMaxEpochEvents, 3, 10, r, consensustest.ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				input.SetEvent(e)
+				assertar.NoError(lch.Process(e))
+			},
+			Build: func(e consensus.MutableEvent, name string) error {
+				if epoch != lch.store.GetEpoch() {
+					return errors.New("epoch already sealed, skip")
+				}
+				e.SetEpoch(epoch)
+				return lch.Build(e)
+			},
+		})
+	}
+	if !assertar.Equal(consensus.Epoch(epochs+1), lch.store.GetEpoch()) {
+		return
+	}
+
+	// only the last 2 sealed epochs are kept
+	logged := 0
+	err := lch.store.ForEachBlockRecord(consensusstore.BlockKey{}, func(key consensusstore.BlockKey, record *consensusstore.BlockRecord) bool {
+		block := lch.blocks[BlockKey{key.Epoch, key.Frame}]
+		if !assertar.NotNil(block) {
+			return false
+		}
+		assertar.GreaterOrEqual(key.Epoch, consensus.Epoch(epochs-1))
+		assertar.Equal(block.Atropos, record.Atropos)
+		assertar.Equal(block.Cheaters, record.Cheaters)
+		assertar.Equal(block.Validators.Hash(), record.ValidatorsHash)
+		logged++
+		return true
+	})
+	assertar.NoError(err)
+	assertar.Equal(int(lch.epochBlocks[epochs-1]+lch.epochBlocks[epochs]), logged)
+
+	record, err := lch.store.GetBlockRecord(consensusstore.BlockKey{Epoch: epochs, Frame: 1})
+	assertar.NoError(err)
+	assertar.Equal(lch.blocks[BlockKey{epochs, 1}].Atropos, record.Atropos)
+}
+
+func TestBlockLog_Disabled(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	consensustest.ForEachRandEvent(nodes, TestMaxEpochEvents, 3, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if !assertar.NotEmpty(lch.blocks) {
+		return
+	}
+
+	// the block log is opt-in
+	logged := 0
+	err := lch.store.ForEachBlockRecord(consensusstore.BlockKey{}, func(key consensusstore.BlockKey, record *consensusstore.BlockRecord) bool {
+		logged++
+		return true
+	})
+	assertar.NoError(err)
+	assertar.Zero(logged)
+}
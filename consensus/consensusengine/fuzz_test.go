@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// FuzzProcess processes the DAGs decoded from the arbitrary inputs, and checks the invariants of the consensus.
+// The corpus of testdata/fuzz/FuzzProcess keeps the minimized interesting inputs and crashers, it runs as a regular test.
+// The long minimization of the inputs slows the fuzzing down, so run it by "make fuzz".
+func FuzzProcess(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 1, 0xff, 2, 0xff, 3, 0xff})
+	for seed := int64(0); seed < 4; seed++ {
+		r := rand.New(rand.NewSource(seed)) // nolint:gosec
+		data := make([]byte, 1+consensustest.MaxFuzzValidators+3*consensustest.MaxFuzzEvents)
+		r.Read(data)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		testProcessInvariants(t, consensustest.DecodeFuzzDAG(data))
+	})
+}
+
+// testProcessInvariants processes the DAG, and checks that:
+//   - the events are built and processed without errors;
+//   - frames never decrease along the self-parents;
+//   - at most one atropos is decided per frame, and it's a root of the frame;
+//   - the cheaters of the blocks are the validators which created forks;
+//   - an event doesn't forkless cause two forks, nor an event of a cheater forkless causes its forkless causer.
+func testProcessInvariants(t *testing.T, dag *consensustest.FuzzDAG) {
+	assertar := assert.New(t)
+	lch, store, input, dagIndexer := NewCoreLachesis(dag.Nodes, dag.Weights)
+
+	processed := map[consensus.EventHash]consensus.Event{}
+	atropoi := map[consensus.Frame]consensus.EventHash{}
+	var cheaters consensus.Cheaters
+	lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		frame := store.GetLastDecidedFrame() + 1
+		prev, ok := atropoi[frame]
+		assertar.False(ok, "frame %d is decided twice: %s and %s", frame, prev.String(), block.Atropos.String())
+		atropoi[frame] = block.Atropos
+		assertar.Equal(frame, processed[block.Atropos].Frame(), "frame of atropos %s", block.Atropos.String())
+		cheaters = append(cheaters, block.Cheaters...)
+		return nil
+	}
+
+	events := dag.ForEach(consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e), "process %s", name)
+			processed[e.ID()] = e
+			if sp := e.SelfParent(); sp != nil {
+				assertar.LessOrEqual(processed[*sp].Frame(), e.Frame(), "frame of %s", name)
+			}
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			err := lch.Build(e)
+			assertar.NoError(err, "build %s", name)
+			return err
+		},
+	})
+	if t.Failed() {
+		return
+	}
+
+	var equivocators []consensus.ValidatorID
+	for _, creator := range dag.Nodes {
+		if hasForks(events[creator]) {
+			equivocators = append(equivocators, creator)
+		}
+	}
+	for _, cheater := range cheaters {
+		assertar.Contains(equivocators, cheater, "cheater %d didn't create forks", cheater)
+	}
+
+	for _, creator := range equivocators {
+		own := events[creator]
+		for _, a := range processed {
+			for i, b1 := range own {
+				fc1, err := dagIndexer.ForklessCause(a.ID(), b1.ID())
+				if !assertar.NoError(err) || !fc1 {
+					continue
+				}
+				if a.ID() != b1.ID() {
+					back, err := dagIndexer.ForklessCause(b1.ID(), a.ID())
+					assertar.NoError(err)
+					assertar.False(back, "%s and %s forkless cause each other", a.ID().String(), b1.ID().String())
+				}
+				for _, b2 := range own[i+1:] {
+					if !areForks(processed, b1, b2) {
+						continue
+					}
+					fc2, err := dagIndexer.ForklessCause(a.ID(), b2.ID())
+					assertar.NoError(err)
+					assertar.False(fc2, "%s forkless causes forks %s and %s", a.ID().String(), b1.ID().String(), b2.ID().String())
+				}
+			}
+		}
+	}
+}
+
+// hasForks returns true if the events of a validator don't form a single self-parent chain
+func hasForks(own consensus.Events) bool {
+	for i, e := range own {
+		for _, other := range own[:i] {
+			if e.Seq() == other.Seq() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// areForks returns true if neither of the events of the same validator is a self-ancestor of the other one
+func areForks(events map[consensus.EventHash]consensus.Event, a, b consensus.Event) bool {
+	isSelfAncestor := func(ancestor, e consensus.Event) bool {
+		for e != nil && e.Seq() > ancestor.Seq() {
+			sp := e.SelfParent()
+			if sp == nil {
+				return false
+			}
+			e = events[*sp]
+		}
+		return e != nil && e.ID() == ancestor.ID()
+	}
+	return !isSelfAncestor(a, b) && !isSelfAncestor(b, a)
+}
@@ -39,7 +39,7 @@ type DagIndexer interface {
 	dagidx.ForklessCause
 
 	Add(consensus.Event) error
-	Flush()
+	Flush() error
 	DropNotFlushed()
 
 	Reset(validators *consensus.Validators, db kvdb.FlushableKVStore, getEvent func(consensus.EventHash) consensus.Event)
@@ -64,7 +64,7 @@ func (p *IndexedLachesis) Build(e consensus.MutableEvent) error {
 	defer p.DagIndexer.DropNotFlushed()
 	err := p.DagIndexer.Add(e)
 	if err != nil {
-		return err
+		return p.fail(err)
 	}
 
 	return p.Lachesis.Build(e)
@@ -78,15 +78,14 @@ func (p *IndexedLachesis) Process(e consensus.Event) (err error) {
 	defer p.DagIndexer.DropNotFlushed()
 	err = p.DagIndexer.Add(e)
 	if err != nil {
-		return err
+		return p.fail(err)
 	}
 
 	err = p.Lachesis.Process(e)
 	if err != nil {
 		return err
 	}
-	p.DagIndexer.Flush()
-	return nil
+	return p.fail(p.DagIndexer.Flush())
 }
 
 func (p *IndexedLachesis) Bootstrap(callback consensus.ConsensusCallbacks) error {
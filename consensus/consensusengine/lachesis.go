@@ -31,6 +31,9 @@ type Lachesis struct {
 	*Orderer
 	dagIndex DagIndex
 	callback consensus.ConsensusCallbacks
+
+	// validatorsHash is the hash of the validators of the current epoch, it's calculated once per epoch for the block log
+	validatorsHash consensus.Hash
 }
 
 // NewLachesis creates Lachesis instance.
@@ -44,22 +47,68 @@ func NewLachesis(store *consensusstore.Store, input EventSource, dagIndex DagInd
 }
 
 func (p *Lachesis) confirmEvents(frame consensus.Frame, atropos consensus.EventHash, onEventConfirmed func(consensus.Event)) error {
-	err := p.dfsSubgraph(atropos, func(e consensus.Event) bool {
-		decidedFrame := p.store.GetEventConfirmedOn(e.ID())
+	if p.config.ConfirmedEventsOrder != nil {
+		return p.confirmOrderedEvents(frame, atropos, onEventConfirmed)
+	}
+	position := uint32(0)
+	err := p.dfsSubgraph(atropos, func(e consensus.Event) (bool, error) {
+		decidedFrame, err := p.store.GetEventConfirmedOn(e.ID())
+		if err != nil {
+			return false, err
+		}
 		if decidedFrame != 0 {
-			return false
+			return false, nil
 		}
 		// mark all the walked events as confirmed
-		p.store.SetEventConfirmedOn(e.ID(), frame)
+		confirmation := consensusstore.EventConfirmation{Frame: frame, Atropos: atropos, Position: position}
+		if err := p.store.SetEventConfirmation(e.ID(), confirmation); err != nil {
+			return false, err
+		}
+		position++
 		if onEventConfirmed != nil {
 			onEventConfirmed(e)
 		}
-		return true
+		return true, nil
 	})
 	return err
 }
 
-func (p *Lachesis) applyAtropos(decidedFrame consensus.Frame, atropos consensus.EventHash) *consensus.Validators {
+// confirmOrderedEvents collects the newly confirmed events first, and then confirms them in the configured order
+func (p *Lachesis) confirmOrderedEvents(frame consensus.Frame, atropos consensus.EventHash, onEventConfirmed func(consensus.Event)) error {
+	var confirmed consensus.Events
+	visited := consensus.EventHashSet{}
+	err := p.dfsSubgraph(atropos, func(e consensus.Event) (bool, error) {
+		if visited.Contains(e.ID()) {
+			return false, nil
+		}
+		decidedFrame, err := p.store.GetEventConfirmedOn(e.ID())
+		if err != nil {
+			return false, err
+		}
+		if decidedFrame != 0 {
+			return false, nil
+		}
+		visited.Add(e.ID())
+		confirmed = append(confirmed, e)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for position, e := range orderEvents(confirmed, p.config.ConfirmedEventsOrder) {
+		confirmation := consensusstore.EventConfirmation{Frame: frame, Atropos: atropos, Position: uint32(position)}
+		if err := p.store.SetEventConfirmation(e.ID(), confirmation); err != nil {
+			return err
+		}
+		if onEventConfirmed != nil {
+			onEventConfirmed(e)
+		}
+	}
+	return nil
+}
+
+func (p *Lachesis) applyAtropos(decidedFrame consensus.Frame, atropos consensus.EventHash) (*consensus.Validators, error) {
 	atroposVecClock := p.dagIndex.GetMergedHighestBefore(atropos)
 
 	validators := p.store.GetValidators()
@@ -71,8 +120,20 @@ func (p *Lachesis) applyAtropos(decidedFrame consensus.Frame, atropos consensus.
 		}
 	}
 
+	// block is logged before it's applied, so that a repeated application after a restart overwrites the same record
+	if p.config.BlockLog {
+		err := p.store.SetBlockRecord(consensusstore.BlockKey{Epoch: p.store.GetEpoch(), Frame: decidedFrame}, &consensusstore.BlockRecord{
+			Atropos:        atropos,
+			Cheaters:       cheaters,
+			ValidatorsHash: p.validatorsHash,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if p.callback.BeginBlock == nil {
-		return nil
+		return nil, nil
 	}
 	blockCallback := p.callback.BeginBlock(&consensus.Block{
 		Atropos:  atropos,
@@ -82,13 +143,28 @@ func (p *Lachesis) applyAtropos(decidedFrame consensus.Frame, atropos consensus.
 	// traverse newly confirmed events
 	err := p.confirmEvents(decidedFrame, atropos, blockCallback.ApplyEvent)
 	if err != nil {
-		p.crit(err)
+		return nil, err
 	}
 
-	if blockCallback.EndBlock != nil {
-		return blockCallback.EndBlock()
+	if blockCallback.EndBlock == nil {
+		return nil, nil
 	}
-	return nil
+	sealEpoch := blockCallback.EndBlock()
+	if sealEpoch != nil && p.config.BlockLog {
+		if err := p.pruneBlockLog(); err != nil {
+			return nil, err
+		}
+	}
+	return sealEpoch, nil
+}
+
+// pruneBlockLog drops the epochs which are out of the block log retention, once the current epoch is sealed
+func (p *Lachesis) pruneBlockLog() error {
+	epoch := p.store.GetEpoch()
+	if p.config.BlockLogEpochs == 0 || epoch < p.config.BlockLogEpochs {
+		return nil
+	}
+	return p.store.PruneBlockLog(epoch - p.config.BlockLogEpochs + 1)
 }
 
 func (p *Lachesis) Bootstrap(callback consensus.ConsensusCallbacks) error {
@@ -106,6 +182,14 @@ func (p *Lachesis) BootstrapWithOrderer(callback consensus.ConsensusCallbacks, o
 
 func (p *Lachesis) OrdererCallbacks() OrdererCallbacks {
 	return OrdererCallbacks{
-		ApplyAtropos: p.applyAtropos,
+		ApplyAtropos:  p.applyAtropos,
+		EpochDBLoaded: p.onEpochDBLoaded,
+	}
+}
+
+// onEpochDBLoaded is called once the validators of the epoch are known, on Bootstrap, Reset and epoch sealing
+func (p *Lachesis) onEpochDBLoaded(consensus.Epoch) {
+	if p.config.BlockLog {
+		p.validatorsHash = p.store.GetValidators().Hash()
 	}
 }
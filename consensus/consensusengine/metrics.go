@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"time"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/utils/metrics"
+)
+
+// Names of the consensus metrics.
+const (
+	MetricEventsProcessed    = "consensus_events_processed"
+	MetricRoots              = "consensus_roots"
+	MetricFramesDecided      = "consensus_frames_decided"
+	MetricFrameRoots         = "consensus_frame_roots"
+	MetricDecisionLatency    = "consensus_decision_latency_seconds"
+	MetricElectionMatrixSize = "consensus_election_matrix_size"
+)
+
+type ordererMetrics struct {
+	// enabled is false if the metrics are discarded, so the values which aren't known by the orderer needn't be loaded
+	enabled bool
+
+	eventsProcessed    metrics.Counter
+	roots              metrics.Counter
+	framesDecided      metrics.Counter
+	frameRoots         metrics.Histogram
+	decisionLatency    metrics.Histogram
+	electionMatrixSize metrics.Gauge
+
+	// frameStarts are the times of the first roots of the undecided frames
+	frameStarts map[consensus.Frame]time.Time
+}
+
+func newOrdererMetrics(registry metrics.Registry) *ordererMetrics {
+	enabled := registry != nil && registry != metrics.Nop
+	registry = metrics.OrNop(registry)
+	return &ordererMetrics{
+		enabled:            enabled,
+		eventsProcessed:    registry.Counter(MetricEventsProcessed),
+		roots:              registry.Counter(MetricRoots),
+		framesDecided:      registry.Counter(MetricFramesDecided),
+		frameRoots:         registry.Histogram(MetricFrameRoots),
+		decisionLatency:    registry.Histogram(MetricDecisionLatency),
+		electionMatrixSize: registry.Gauge(MetricElectionMatrixSize),
+		frameStarts:        make(map[consensus.Frame]time.Time),
+	}
+}
+
+func (m *ordererMetrics) onRoot(frame consensus.Frame) {
+	m.roots.Inc(1)
+	if _, ok := m.frameStarts[frame]; !ok {
+		m.frameStarts[frame] = time.Now()
+	}
+}
+
+// onFrameDecided observes the frame decision, the decision latency is known only for the frames whose roots were processed since start
+func (m *ordererMetrics) onFrameDecided(frame consensus.Frame, rootsNum int) {
+	m.framesDecided.Inc(1)
+	m.frameRoots.Observe(float64(rootsNum))
+	if start, ok := m.frameStarts[frame]; ok {
+		m.decisionLatency.Observe(time.Since(start).Seconds())
+	}
+	for f := range m.frameStarts {
+		if f <= frame {
+			delete(m.frameStarts, f)
+		}
+	}
+}
+
+func (m *ordererMetrics) onEpochSwitched() {
+	m.frameStarts = make(map[consensus.Frame]time.Time)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+	"github.com/0xsoniclabs/consensus/utils/metrics"
+)
+
+func TestOrdererMetrics(t *testing.T) {
+	assertar := assert.New(t)
+
+	registry := metrics.NewMemoryRegistry()
+	config := LiteConfig()
+	config.Metrics = registry
+	nodes := consensustest.GenNodes(5)
+	lch, _, input, _ := newCoreLachesis(nodes, nil, config)
+
+	var (
+		events int64
+		roots  int64
+	)
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	consensustest.ForEachRandEvent(nodes, TestMaxEpochEvents, len(nodes), r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+			events++
+			if e.SelfParent() == nil || input.GetEvent(*e.SelfParent()).Frame() != e.Frame() {
+				roots++
+			}
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if !assertar.NotEmpty(lch.blocks) {
+		return
+	}
+
+	decided := int64(len(lch.blocks))
+	assertar.Equal(events, registry.GetCounter(MetricEventsProcessed).Value())
+	assertar.Equal(roots, registry.GetCounter(MetricRoots).Value())
+	assertar.Equal(decided, registry.GetCounter(MetricFramesDecided).Value())
+
+	frameRoots := registry.GetHistogram(MetricFrameRoots).Snapshot()
+	assertar.Equal(uint64(decided), frameRoots.Count)
+	assertar.GreaterOrEqual(frameRoots.Min, float64(len(nodes)*2/3+1))
+	assertar.LessOrEqual(frameRoots.Max, float64(len(nodes)))
+
+	latency := registry.GetHistogram(MetricDecisionLatency).Snapshot()
+	assertar.Equal(uint64(decided), latency.Count)
+	assertar.GreaterOrEqual(latency.Min, 0.0)
+
+	assertar.Positive(registry.GetGauge(MetricElectionMatrixSize).Value())
+}
+
+func TestOrdererMetrics_Disabled(t *testing.T) {
+	assertar := assert.New(t)
+
+	assertar.False(newOrdererMetrics(nil).enabled)
+	assertar.False(newOrdererMetrics(metrics.Nop).enabled)
+	assertar.True(newOrdererMetrics(metrics.NewMemoryRegistry()).enabled)
+
+	// the frames are decided without the metrics
+	nodes := consensustest.GenNodes(5)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+	consensustest.ForEachRandEvent(nodes, TestMaxEpochEvents, len(nodes), nil, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	assertar.NotEmpty(lch.blocks)
+}
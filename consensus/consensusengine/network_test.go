@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// simLachesis is a node of consensustest.Network, which records the confirmed blocks
+type simLachesis struct {
+	*CoreLachesis
+	input *consensustest.TestEventSource
+
+	blocks  []simBlock
+	applied consensus.EventHashes
+}
+
+// simBlock is a block along with the events passed to ApplyEvent
+type simBlock struct {
+	Atropos  consensus.EventHash
+	Cheaters consensus.Cheaters
+	Events   consensus.EventHashSet
+}
+
+func newSimLachesis(nodes []consensus.ValidatorID, weights []consensus.Weight) *simLachesis {
+	lch, _, input, _ := NewCoreLachesis(nodes, weights)
+	node := &simLachesis{CoreLachesis: lch, input: input}
+	lch.applyEvent = func(e consensus.Event) {
+		node.applied = append(node.applied, e.ID())
+	}
+	lch.applyBlock = func(block *consensus.Block) *consensus.Validators {
+		node.blocks = append(node.blocks, simBlock{
+			Atropos:  block.Atropos,
+			Cheaters: block.Cheaters,
+			Events:   node.applied.Set(),
+		})
+		node.applied = nil
+		return nil
+	}
+	return node
+}
+
+func (n *simLachesis) Build(e consensus.MutableEvent) error {
+	e.SetEpoch(consensus.FirstEpoch)
+	return n.CoreLachesis.Build(e)
+}
+
+func (n *simLachesis) Process(e consensus.Event) error {
+	n.input.SetEvent(e)
+	return n.CoreLachesis.Process(e)
+}
+
+func TestNetwork_Latency(t *testing.T) {
+	testNetwork(t, consensustest.GenNodes(5), nil, consensustest.NetworkConfig{
+		MinLatency: 1,
+		MaxLatency: 5,
+	})
+}
+
+func TestNetwork_Reordering(t *testing.T) {
+	testNetwork(t, consensustest.GenNodes(7), []consensus.Weight{1, 2, 3, 4, 5, 6, 7}, consensustest.NetworkConfig{
+		MinLatency: 0,
+		MaxLatency: 100,
+	})
+}
+
+func TestNetwork_Partitions(t *testing.T) {
+	nodes := consensustest.GenNodes(7)
+	testNetwork(t, nodes, nil, consensustest.NetworkConfig{
+		MinLatency: 1,
+		MaxLatency: 20,
+		Partitions: []consensustest.Partition{
+			// neither side has a quorum
+			{From: 200, To: 600, Group: nodes[:4]},
+			// the majority side keeps deciding
+			{From: 1000, To: 1500, Group: nodes[:2]},
+			// the partition heals after the emission stops
+			{From: 1800, To: 3000, Group: nodes[3:]},
+		},
+	})
+}
+
+// testNetwork runs a Lachesis per validator, connected by the network, and checks that every node
+// confirms the same blocks with the same events, regardless of the order in which the node received the events
+func testNetwork(t *testing.T, nodes []consensus.ValidatorID, weights []consensus.Weight, config consensustest.NetworkConfig) {
+	t.Helper()
+	config.Duration = 2000
+	config.EmitInterval = 20
+	config.Parents = len(nodes)/2 + 1
+
+	for seed := int64(0); seed < 3; seed++ {
+		assertar := assert.New(t)
+
+		network := consensustest.NewNetwork(config, rand.New(rand.NewSource(seed))) // nolint:gosec
+		lchs := make([]*simLachesis, 0, len(nodes))
+		for _, v := range nodes {
+			lch := newSimLachesis(nodes, weights)
+			lchs = append(lchs, lch)
+			network.AddNode(v, lch)
+		}
+		if !assertar.NoError(network.Run(), "seed %d", seed) {
+			return
+		}
+
+		if !assertar.NotEmpty(lchs[0].blocks, "seed %d", seed) {
+			return
+		}
+		confirmed := consensus.EventHashSet{}
+		for _, block := range lchs[0].blocks {
+			for e := range block.Events {
+				assertar.False(confirmed.Contains(e), "event %s is confirmed twice", e.String())
+				confirmed.Add(e)
+			}
+			assertar.True(block.Events.Contains(block.Atropos))
+		}
+		for i, lch := range lchs[1:] {
+			assertar.Equal(lchs[0].blocks, lch.blocks, "seed %d, node %d", seed, i+1)
+		}
+	}
+}
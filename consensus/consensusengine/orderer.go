@@ -11,13 +11,15 @@
 package consensusengine
 
 import (
+	"errors"
+
 	"github.com/0xsoniclabs/consensus/consensus"
 	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
 	"github.com/0xsoniclabs/consensus/dagidx"
 )
 
 type OrdererCallbacks struct {
-	ApplyAtropos func(decidedFrame consensus.Frame, atropos consensus.EventHash) (sealEpoch *consensus.Validators)
+	ApplyAtropos func(decidedFrame consensus.Frame, atropos consensus.EventHash) (sealEpoch *consensus.Validators, err error)
 
 	EpochDBLoaded func(consensus.Epoch)
 }
@@ -34,8 +36,9 @@ type Orderer struct {
 	store  *consensusstore.Store
 	Input  EventSource
 
-	election *election
+	election Election
 	dagIndex OrdererDagIndex
+	metrics  *ordererMetrics
 
 	callback OrdererCallbacks
 }
@@ -43,6 +46,8 @@ type Orderer struct {
 // NewOrderer creates Orderer instance.
 // Unlike Lachesis, Orderer doesn't updates DAG indexes for events, and doesn't detect cheaters
 // It has only one purpose - reaching consensus on events order.
+// Integrity errors (wrapping consensus.ErrIntegrity) are returned to the caller,
+// crit is optional and is additionally called for each of them.
 func NewOrderer(store *consensusstore.Store, input EventSource, dagIndex OrdererDagIndex, crit func(error), config Config) *Orderer {
 	p := &Orderer{
 		config:   config,
@@ -50,7 +55,16 @@ func NewOrderer(store *consensusstore.Store, input EventSource, dagIndex Orderer
 		Input:    input,
 		crit:     crit,
 		dagIndex: dagIndex,
+		metrics:  newOrdererMetrics(config.Metrics),
 	}
 
 	return p
 }
+
+// fail passes integrity errors to the optional crit callback.
+func (p *Orderer) fail(err error) error {
+	if p.crit != nil && errors.Is(err, consensus.ErrIntegrity) {
+		p.crit(err)
+	}
+	return err
+}
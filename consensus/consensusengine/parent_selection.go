@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/dagidx"
+)
+
+// ErrParentSelectionNotSupported is returned by ChooseParents if the DagIndexer isn't a dagidx.ParentSelector.
+var ErrParentSelectionNotSupported = errors.New("parent selection isn't supported by the DAG indexer")
+
+// ChooseParents chooses the parents of a new event on top of the self-parent among the heads, see dagidx.ParentSelector.
+// The heads are ranked by the progress of forkless causing the roots of the highest frame of the self-parent
+// and the heads, i.e. the roots which the new event must forkless cause by quorum to become a root of the next frame.
+// ErrParentSelectionNotSupported is returned if the DagIndexer isn't a dagidx.ParentSelector.
+// ChooseParents is not safe for concurrent use.
+func (p *IndexedLachesis) ChooseParents(selfParent consensus.EventHash, heads consensus.EventHashes, maxParents int) (consensus.EventHashes, error) {
+	selector, ok := p.DagIndexer.(dagidx.ParentSelector)
+	if !ok {
+		return nil, errors.Wrapf(ErrParentSelectionNotSupported, "%T", p.DagIndexer)
+	}
+	frame, err := p.highestFrame(append(consensus.EventHashes{selfParent}, heads...))
+	if err != nil {
+		return nil, err
+	}
+	frameRoots, err := p.store.GetFrameRoots(frame)
+	if err != nil {
+		return nil, err
+	}
+	roots := make(consensus.EventHashes, 0, len(frameRoots))
+	for _, root := range frameRoots {
+		roots = append(roots, root.RootHash)
+	}
+	return selector.ChooseParents(selfParent, heads, roots, maxParents)
+}
+
+// highestFrame returns the highest frame of the events, like calcFrameIdx does for the parents of an event
+func (p *Orderer) highestFrame(events consensus.EventHashes) (consensus.Frame, error) {
+	frame := consensus.Frame(0)
+	for _, id := range events {
+		e := p.Input.GetEvent(id)
+		if e == nil {
+			return 0, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "event %s not found", id.String())
+		}
+		frame = max(frame, e.Frame())
+	}
+	return frame, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+type chooseParentsFn func(selfParent consensus.EventHash, heads consensus.EventHashes, maxParents int) (consensus.EventHashes, error)
+
+func TestIndexedLachesis_ChooseParents(t *testing.T) {
+	for _, maxParents := range []int{2, 3, 5} {
+		t.Run(fmt.Sprintf("max=%d", maxParents), func(t *testing.T) {
+			assertar := assert.New(t)
+
+			chosen := testEmitWithParents(t, maxParents, func(lch *CoreLachesis) chooseParentsFn {
+				return lch.ChooseParents
+			})
+			// random heads
+			r := rand.New(rand.NewSource(1)) // nolint:gosec
+			random := testEmitWithParents(t, maxParents, func(*CoreLachesis) chooseParentsFn {
+				return func(selfParent consensus.EventHash, heads consensus.EventHashes, maxParents int) (consensus.EventHashes, error) {
+					r.Shuffle(len(heads), func(i, j int) {
+						heads[i], heads[j] = heads[j], heads[i]
+					})
+					return append(consensus.EventHashes{selfParent}, heads[:min(len(heads), maxParents-1)]...), nil
+				}
+			})
+			assertar.Greater(chosen, random, "frames reached")
+		})
+	}
+}
+
+// testEmitWithParents emits the events in a random order of the validators, every event is on top of the latest
+// events of all the validators, and the parents are chosen among them. Returns the highest frame reached.
+func testEmitWithParents(t *testing.T, maxParents int, choose func(*CoreLachesis) chooseParentsFn) consensus.Frame {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(10)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+	chooseParents := choose(lch)
+
+	r := rand.New(rand.NewSource(0)) // nolint:gosec
+	latest := map[consensus.ValidatorID]consensus.Event{}
+	frame := consensus.Frame(0)
+	for i := 0; i < 50*len(nodes); i++ {
+		creator := nodes[r.Intn(len(nodes))]
+		e := &consensustest.TestEvent{}
+		e.SetCreator(creator)
+		e.SetEpoch(consensus.FirstEpoch)
+		e.SetSeq(1)
+		e.SetLamport(1)
+		e.SetParents(consensus.EventHashes{})
+		if selfParent, ok := latest[creator]; ok {
+			var heads consensus.EventHashes
+			for _, v := range nodes {
+				if head, ok := latest[v]; ok && v != creator {
+					heads = append(heads, head.ID())
+				}
+			}
+			parents, err := chooseParents(selfParent.ID(), heads, maxParents)
+			if !assertar.NoError(err) {
+				return 0
+			}
+			assertar.Equal(selfParent.ID(), parents[0])
+			assertar.LessOrEqual(len(parents), maxParents)
+			e.SetSeq(selfParent.Seq() + 1)
+			for _, p := range parents {
+				parent := input.GetEvent(p)
+				e.AddParent(p)
+				e.SetLamport(max(e.Lamport(), parent.Lamport()+1))
+			}
+		}
+		e.Name = fmt.Sprintf("%d_%d", creator, e.Seq())
+		if !assertar.NoError(lch.Build(e)) {
+			return 0
+		}
+		e.SetID(consensustest.CalcHashForTestEvent(e))
+		input.SetEvent(e)
+		if !assertar.NoError(lch.Process(e)) {
+			return 0
+		}
+		latest[creator] = e
+		frame = max(frame, e.Frame())
+	}
+	return frame
+}
+
+// noParentSelector is a DagIndexer which doesn't implement dagidx.ParentSelector
+type noParentSelector struct {
+	DagIndexer
+}
+
+func TestIndexedLachesis_ChooseParents_NotSupported(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(3)
+	lch, _, _, dagIndexer := NewCoreLachesis(nodes, nil)
+	lch.DagIndexer = noParentSelector{dagIndexer}
+	_, err := lch.ChooseParents(consensus.EventHash{1}, nil, 2)
+	assertar.ErrorIs(err, ErrParentSelectionNotSupported)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+func TestLachesisModel_Differential(t *testing.T) {
+	for _, impl := range electionImplementations {
+		for _, test := range []struct {
+			weights  []consensus.Weight
+			cheaters int
+			forks    int
+		}{
+			{weights: []consensus.Weight{1, 1, 1, 1}},
+			{weights: []consensus.Weight{1, 2, 3, 4, 5}},
+			{weights: []consensus.Weight{5, 5, 5, 5, 5, 5, 5}, cheaters: 1, forks: 10},
+			{weights: []consensus.Weight{1, 1, 1, 1, 1, 1, 1, 1}, cheaters: 2, forks: 10},
+			{weights: []consensus.Weight{1, 1, 1, 1, 1, 1, 11}, cheaters: 2, forks: 5},
+		} {
+			for seed := int64(0); seed < 3; seed++ {
+				t.Run(fmt.Sprintf("%s/%v/cheaters=%d/seed=%d", impl.name, test.weights, test.cheaters, seed), func(t *testing.T) {
+					testLachesisModelDifferential(t, impl.newElection, test.weights, test.cheaters, test.forks, seed)
+				})
+			}
+		}
+	}
+}
+
+// testLachesisModelDifferential runs a random DAG through both the engine with the election and the reference model,
+// and compares the results
+func testLachesisModelDifferential(t *testing.T, newElection NewElectionFn, weights []consensus.Weight, cheatersCount, forksCount int, seed int64) {
+	assertar := assert.New(t)
+
+	config := LiteConfig()
+	config.NewElection = newElection
+	nodes := consensustest.GenNodes(len(weights))
+	lch, store, input, _ := newCoreLachesis(nodes, weights, config)
+	model := consensustest.NewLachesisModel(store.GetValidators())
+
+	r := rand.New(rand.NewSource(seed)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:cheatersCount], 20*len(nodes), len(nodes)/2+1, forksCount, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(lch.Process(e))
+			assertar.NoError(model.Add(e))
+			assertar.Equal(model.Frame(e.ID()), e.Frame(), "frame of %s", name)
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(consensus.FirstEpoch)
+			return lch.Build(e)
+		},
+	})
+	if t.Failed() {
+		return
+	}
+
+	for frame := consensus.FirstFrame; ; frame++ {
+		frameRoots, err := store.GetFrameRoots(frame)
+		assertar.NoError(err)
+		roots := consensus.EventHashSet{}
+		for _, root := range frameRoots {
+			roots.Add(root.RootHash)
+		}
+		assertar.Equal(model.Roots(frame).Set(), roots, "roots of frame %d", frame)
+		if len(frameRoots) == 0 {
+			break
+		}
+	}
+
+	blocks, err := model.Blocks()
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.NotEmpty(blocks)
+	assertar.Len(lch.blocks, len(blocks))
+	for _, block := range blocks {
+		got := lch.blocks[BlockKey{Epoch: consensus.FirstEpoch, Frame: block.Frame}]
+		if !assertar.NotNil(got, "block of frame %d", block.Frame) {
+			continue
+		}
+		assertar.Equal(block.Atropos, got.Atropos, "atropos of frame %d", block.Frame)
+		assertar.Equal(block.Cheaters, got.Cheaters, "cheaters of frame %d", block.Frame)
+	}
+}
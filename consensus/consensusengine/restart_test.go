@@ -44,6 +44,14 @@ func TestRestart_big3(t *testing.T) {
 	testRestart(t, []consensus.Weight{math.MaxUint32 / 8, math.MaxUint32 / 8, math.MaxUint32 / 4}, 0)
 }
 
+func TestRestart_huge2(t *testing.T) {
+	testRestart(t, []consensus.Weight{consensus.MaxTotalWeight / 2, consensus.MaxTotalWeight / 2}, 0)
+}
+
+func TestRestart_huge3(t *testing.T) {
+	testRestart(t, []consensus.Weight{math.MaxUint32 + 1, consensus.MaxTotalWeight / 4, 1}, 0)
+}
+
 func TestRestart_4(t *testing.T) {
 	testRestart(t, []consensus.Weight{1, 2, 3, 4}, 0)
 }
@@ -166,34 +174,8 @@ func testRestartAndReset(t *testing.T, weights []consensus.Weight, mutateWeights
 		}
 		if r.Intn(10) == 0 {
 			prev := lchs[RESTORED]
-
-			store := consensusstore.NewMemStore()
-			// copy prev DB into new one
-			{
-				it := prev.store.MainDB.NewIterator(nil, nil)
-				for it.Next() {
-					assertar.NoError(store.MainDB.Put(it.Key(), it.Value()))
-				}
-				it.Release()
-			}
-			restartEpochDB := memorydb.New()
-			{
-				it := prev.store.EpochDB.NewIterator(nil, nil)
-				for it.Next() {
-					assertar.NoError(restartEpochDB.Put(it.Key(), it.Value()))
-				}
-				it.Release()
-			}
-			restartEpoch := prev.store.GetEpoch()
-			store.GetEpochDB = func(epoch consensus.Epoch) kvdb.Store {
-				if epoch == restartEpoch {
-					return restartEpochDB
-				}
-				return memorydb.New()
-			}
-
-			restored := NewIndexedLachesis(store, prev.Input, &adapters.VectorToDagIndexer{Engine: vecengine.NewIndex(prev.crit, vecengine.LiteConfig(), vecengine.GetEngineCallbacks)}, prev.crit, prev.config)
-			assertar.NoError(restored.Bootstrap(prev.callback))
+			restored := restartLachesis(assertar, prev)
+			compareElections(assertar, lchs[EXPECTED].election.(*election), restored.election.(*election))
 
 			lchs[RESTORED].IndexedLachesis = restored
 		}
@@ -219,6 +201,38 @@ func testRestartAndReset(t *testing.T, weights []consensus.Weight, mutateWeights
 	compareBlocks(assertar, lchs[EXPECTED], lchs[RESTORED])
 }
 
+// restartLachesis creates a new instance on a copy of the DBs of prev, and bootstraps it like after a restart
+func restartLachesis(assertar *assert.Assertions, prev *CoreLachesis) *IndexedLachesis {
+	store := consensusstore.NewMemStore()
+	// copy prev DB into new one
+	{
+		it := prev.store.MainDB.NewIterator(nil, nil)
+		for it.Next() {
+			assertar.NoError(store.MainDB.Put(it.Key(), it.Value()))
+		}
+		it.Release()
+	}
+	restartEpochDB := memorydb.New()
+	{
+		it := prev.store.EpochDB.NewIterator(nil, nil)
+		for it.Next() {
+			assertar.NoError(restartEpochDB.Put(it.Key(), it.Value()))
+		}
+		it.Release()
+	}
+	restartEpoch := prev.store.GetEpoch()
+	store.GetEpochDB = func(epoch consensus.Epoch) kvdb.Store {
+		if epoch == restartEpoch {
+			return restartEpochDB
+		}
+		return memorydb.New()
+	}
+
+	restored := NewIndexedLachesis(store, prev.Input, &adapters.VectorToDagIndexer{Engine: vecengine.NewIndex(prev.crit, vecengine.LiteConfig(), vecengine.GetEngineCallbacks)}, prev.crit, prev.config)
+	assertar.NoError(restored.Bootstrap(prev.callback))
+	return restored
+}
+
 func compareStates(assertar *assert.Assertions, expected, restored *CoreLachesis) {
 	assertar.Equal(
 		*(expected.store.GetLastDecidedState()), *(restored.store.GetLastDecidedState()))
@@ -234,6 +248,31 @@ func compareStates(assertar *assert.Assertions, expected, restored *CoreLachesis
 	}
 }
 
+// compareElections checks that the election state restored from the checkpoint matches the sample
+func compareElections(assertar *assert.Assertions, expected, restored *election) {
+	assertar.Equal(expected.frameToDeliver, restored.frameToDeliver)
+	expectedState, err := expected.Checkpoint()
+	assertar.NoError(err)
+	restoredState, err := restored.Checkpoint()
+	assertar.NoError(err)
+	assertar.Equal(expectedState.Decided, restoredState.Decided)
+	if !assertar.Equal(len(expectedState.Votes), len(restoredState.Votes)) {
+		return
+	}
+	// vote matrices may begin with different frames, compare only the non-delivered ones
+	for i, e := range expectedState.Votes {
+		r := restoredState.Votes[i]
+		assertar.Equal(e.RootHash, r.RootHash)
+		eOffset := int(expected.frameToDeliver-e.FrameToDeliverOffset) * int(expected.validatorCount)
+		rOffset := int(restored.frameToDeliver-r.FrameToDeliverOffset) * int(restored.validatorCount)
+		if len(e.VoteMatrix) == 0 || len(r.VoteMatrix) == 0 {
+			assertar.Equal(len(e.VoteMatrix) == 0, len(r.VoteMatrix) == 0)
+			continue
+		}
+		assertar.Equal(e.VoteMatrix[eOffset:], r.VoteMatrix[rOffset:])
+	}
+}
+
 func compareBlocks(assertar *assert.Assertions, expected, restored *CoreLachesis) {
 	assertar.Equal(expected.lastBlock, restored.lastBlock)
 	for e := consensus.Epoch(1); e <= expected.lastBlock.Epoch; e++ {
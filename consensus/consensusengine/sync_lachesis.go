@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"sync"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensusstore"
+	"github.com/0xsoniclabs/consensus/dagidx"
+)
+
+var _ consensus.Consensus = (*SyncLachesis)(nil)
+
+// SyncLachesis is a concurrent-safe wrapper around IndexedLachesis.
+//
+// Concurrency model:
+//   - Build, Process, ProcessBatch, Reset and Bootstrap mutate the consensus state
+//     and are serialized by an exclusive lock. AdviseEmission takes the exclusive lock too,
+//     as it indexes the new event temporarily.
+//   - The queries (GetEpoch, GetValidators, GetLastDecidedFrame, GetFrameRoots, GetEventConfirmedOn,
+//     GetEventConfirmation, GetEventsConfirmedBy, ForklessCause, GetMergedHighestBefore, ChooseParents) take a shared lock,
+//     so they run in parallel with each other and observe the state between two mutations,
+//     but never in the middle of one.
+//   - The consensus callbacks are called with the exclusive lock held,
+//     so they must not call SyncLachesis methods, or they will deadlock.
+//
+// The wrapped IndexedLachesis must not be used directly once it's wrapped.
+type SyncLachesis struct {
+	mu       sync.RWMutex
+	lachesis *IndexedLachesis
+}
+
+// NewSyncLachesis wraps IndexedLachesis instance.
+func NewSyncLachesis(lachesis *IndexedLachesis) *SyncLachesis {
+	return &SyncLachesis{
+		lachesis: lachesis,
+	}
+}
+
+// Build fills consensus-related fields: Frame, IsRoot
+// returns error if event should be dropped
+func (p *SyncLachesis) Build(e consensus.MutableEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.Build(e)
+}
+
+// AdviseEmission calculates the EmissionAdvice for a new event, see IndexedLachesis.AdviseEmission.
+func (p *SyncLachesis) AdviseEmission(selfParent consensus.EventHash, heads consensus.EventHashes) (*EmissionAdvice, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.AdviseEmission(selfParent, heads)
+}
+
+// Process takes event into processing.
+// Event order matter: parents first.
+func (p *SyncLachesis) Process(e consensus.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.Process(e)
+}
+
+// ProcessBatch takes a batch of events into processing, see IndexedLachesis.ProcessBatch.
+func (p *SyncLachesis) ProcessBatch(events consensus.Events) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.ProcessBatch(events)
+}
+
+// Reset switches epoch state to a new empty epoch.
+func (p *SyncLachesis) Reset(epoch consensus.Epoch, validators *consensus.Validators) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.Reset(epoch, validators)
+}
+
+// Bootstrap restores abft's state from store.
+func (p *SyncLachesis) Bootstrap(callback consensus.ConsensusCallbacks) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lachesis.Bootstrap(callback)
+}
+
+// GetEpoch returns the current epoch.
+func (p *SyncLachesis) GetEpoch() consensus.Epoch {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetEpoch()
+}
+
+// GetValidators returns the validators of the current epoch.
+func (p *SyncLachesis) GetValidators() *consensus.Validators {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetValidators()
+}
+
+// GetLastDecidedFrame returns the last decided frame of the current epoch.
+func (p *SyncLachesis) GetLastDecidedFrame() consensus.Frame {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetLastDecidedFrame()
+}
+
+// GetFrameRoots returns a copy of all the roots in the specified frame of the current epoch.
+func (p *SyncLachesis) GetFrameRoots(frame consensus.Frame) ([]consensusstore.RootDescriptor, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	roots, err := p.lachesis.store.GetFrameRoots(frame)
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]consensusstore.RootDescriptor, 0, len(roots)), roots...), nil
+}
+
+// GetEventConfirmedOn returns the frame which confirmed the event, or 0 if it isn't confirmed yet.
+func (p *SyncLachesis) GetEventConfirmedOn(id consensus.EventHash) (consensus.Frame, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetEventConfirmedOn(id)
+}
+
+// GetEventConfirmation returns the block and the position which confirmed the event, or nil if it isn't confirmed yet.
+func (p *SyncLachesis) GetEventConfirmation(id consensus.EventHash) (*consensusstore.EventConfirmation, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetEventConfirmation(id)
+}
+
+// GetEventsConfirmedBy returns the events confirmed by the Atropos of the current epoch, in the order of their positions.
+func (p *SyncLachesis) GetEventsConfirmedBy(atropos consensus.EventHash) (consensus.EventHashes, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.store.GetEventsConfirmedBy(atropos)
+}
+
+// ForklessCause calculates "sufficient coherence" between the events of the current epoch.
+func (p *SyncLachesis) ForklessCause(aID, bID consensus.EventHash) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.DagIndexer.ForklessCause(aID, bID)
+}
+
+// GetMergedHighestBefore returns the highest-before vector of the event of the current epoch.
+func (p *SyncLachesis) GetMergedHighestBefore(id consensus.EventHash) dagidx.HighestBeforeSeq {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.DagIndexer.GetMergedHighestBefore(id)
+}
+
+// ChooseParents chooses the parents of a new event of the current epoch, see IndexedLachesis.ChooseParents.
+func (p *SyncLachesis) ChooseParents(selfParent consensus.EventHash, heads consensus.EventHashes, maxParents int) (consensus.EventHashes, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lachesis.ChooseParents(selfParent, heads, maxParents)
+}
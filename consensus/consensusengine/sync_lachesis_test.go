@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusengine
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+)
+
+// TestSyncLachesis_ConcurrentQueries is meaningful mostly with the race detector enabled.
+func TestSyncLachesis_ConcurrentQueries(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(5)
+	generator, _, generatorInput, _ := NewCoreLachesis(nodes, nil)
+	lch, _, input, _ := NewCoreLachesis(nodes, nil)
+
+	var ordered consensus.Events
+	r := rand.New(rand.NewSource(3)) // nolint:gosec
+	consensustest.ForEachRandFork(nodes, nodes[:1], 300, 3, 10, r, consensustest.ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			ordered = append(ordered, e)
+			generatorInput.SetEvent(e)
+			input.SetEvent(e)
+			assertar.NoError(generator.Process(e))
+		},
+		Build: func(e consensus.MutableEvent, name string) error {
+			e.SetEpoch(1)
+			return generator.Build(e)
+		},
+	})
+
+	synced := NewSyncLachesis(lch.IndexedLachesis)
+	// number of the events which are processed already
+	var processed atomic.Int64
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed)) // nolint:gosec
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				n := processed.Load()
+				if n == 0 {
+					continue
+				}
+				a := ordered[r.Int63n(n)].ID()
+				b := ordered[r.Int63n(n)].ID()
+				_, err := synced.ForklessCause(a, b)
+				assertar.NoError(err)
+				assertar.NotNil(synced.GetMergedHighestBefore(a))
+				parents, err := synced.ChooseParents(a, consensus.EventHashes{b}, 2)
+				assertar.NoError(err)
+				assertar.Equal(a, parents[0])
+				_, err = synced.GetFrameRoots(synced.GetLastDecidedFrame() + 1)
+				assertar.NoError(err)
+				_, err = synced.GetEventConfirmedOn(a)
+				assertar.NoError(err)
+				assertar.Equal(consensus.FirstEpoch, synced.GetEpoch())
+				assertar.NotNil(synced.GetValidators())
+			}
+		}(int64(i))
+	}
+
+	for i, e := range ordered {
+		assertar.NoError(synced.Process(e))
+		processed.Store(int64(i + 1))
+	}
+	close(done)
+	wg.Wait()
+
+	assertar.NotEmpty(generator.blocks)
+	assertar.Equal(generator.blocks, lch.blocks)
+}
@@ -43,10 +43,16 @@ type CoreLachesis struct {
 	lastBlock   BlockKey
 	epochBlocks map[consensus.Epoch]consensus.Frame
 	applyBlock  applyBlockFn
+	applyEvent  func(e consensus.Event)
 }
 
 // NewCoreLachesis creates empty abft consensus with mem store and optional node weights w.o. some callbacks usually instantiated by Client
 func NewCoreLachesis(nodes []consensus.ValidatorID, weights []consensus.Weight, mods ...memorydb.Mod) (*CoreLachesis, *consensusstore.Store, *consensustest.TestEventSource, *adapters.VectorToDagIndexer) {
+	return newCoreLachesis(nodes, weights, LiteConfig(), mods...)
+}
+
+// newCoreLachesis is NewCoreLachesis with the specified config
+func newCoreLachesis(nodes []consensus.ValidatorID, weights []consensus.Weight, config Config, mods ...memorydb.Mod) (*CoreLachesis, *consensusstore.Store, *consensustest.TestEventSource, *adapters.VectorToDagIndexer) {
 	validators := make(consensus.ValidatorsBuilder, len(nodes))
 	for i, v := range nodes {
 		if weights == nil {
@@ -67,12 +73,9 @@ func NewCoreLachesis(nodes []consensus.ValidatorID, weights []consensus.Weight,
 
 	input := consensustest.NewTestEventSource()
 
-	config := LiteConfig()
-	crit := func(err error) {
-		panic(err)
-	}
-	dagIndexer := &adapters.VectorToDagIndexer{Engine: vecengine.NewIndex(crit, vecengine.LiteConfig(), vecengine.GetEngineCallbacks)}
-	lch := NewIndexedLachesis(store, input, dagIndexer, crit, config)
+	// integrity errors are returned by Process and checked by the tests, no crit fallback is needed
+	dagIndexer := &adapters.VectorToDagIndexer{Engine: vecengine.NewIndex(nil, vecengine.LiteConfig(), vecengine.GetEngineCallbacks)}
+	lch := NewIndexedLachesis(store, input, dagIndexer, nil, config)
 
 	extended := &CoreLachesis{
 		IndexedLachesis: lch,
@@ -83,6 +86,11 @@ func NewCoreLachesis(nodes []consensus.ValidatorID, weights []consensus.Weight,
 	err = extended.Bootstrap(consensus.ConsensusCallbacks{
 		BeginBlock: func(block *consensus.Block) consensus.BlockCallbacks {
 			return consensus.BlockCallbacks{
+				ApplyEvent: func(e consensus.Event) {
+					if extended.applyEvent != nil {
+						extended.applyEvent(e)
+					}
+				},
 				EndBlock: func() (sealEpoch *consensus.Validators) {
 					// track blocks
 					key := BlockKey{
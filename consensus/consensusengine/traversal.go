@@ -11,12 +11,10 @@
 package consensusengine
 
 import (
-	"errors"
-
 	"github.com/0xsoniclabs/consensus/consensus"
 )
 
-type eventFilterFn func(event consensus.Event) bool
+type eventFilterFn func(event consensus.Event) (bool, error)
 
 // dfsSubgraph iterates all the events which are observed by head, and accepted by a filter.
 // filter MAY BE called twice for the same event.
@@ -28,11 +26,15 @@ func (p *Orderer) dfsSubgraph(head consensus.EventHash, filter eventFilterFn) er
 
 		event := p.Input.GetEvent(walk)
 		if event == nil {
-			return errors.New("event not found " + walk.String())
+			return consensus.IntegrityErrorf(consensus.ErrMissingEvent, "event not found %s", walk.String())
 		}
 
 		// filter
-		if !filter(event) {
+		ok, err := filter(event)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			continue
 		}
 
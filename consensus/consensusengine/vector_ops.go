@@ -12,27 +12,27 @@ package consensusengine
 
 import "github.com/kelindar/simd"
 
-// addInt32Vecs is to be used only by consensus election:
+// addInt64Vecs is to be used only by consensus election:
 // src1[i] in [-ValidatorXWeight, ValidatorXWeight] and src2[i] in [-ValidatorYWeight, ValidatorYWeight]
-// => |src1[i] + src2[i]| <= ValidatorXWeight + ValidatorYWeight <= TotalValidatorWeight <= max(int32)
-func addInt32Vecs(dst []int32, src1 []int32, src2 []int32) {
+// => |src1[i] + src2[i]| <= ValidatorXWeight + ValidatorYWeight <= TotalValidatorWeight <= max(int64), as TotalValidatorWeight <= MaxTotalWeight
+func addInt64Vecs(dst []int64, src1 []int64, src2 []int64) {
 	if len(src1) == 0 {
 		return
 	}
-	simd.AddInt32s(dst, src1, src2)
+	simd.AddInt64s(dst, src1, src2)
 }
 
-// mulInt32VecWithConst is to be used only by consensus election:
+// mulInt64VecWithConst is to be used only by consensus election:
 // src[i] in [-1, 1] and num in [0, ValidatorXWeight]
-// => |src[i] * num| <= ValidatorXWeight <= TotalValidatorWeight <= max(int32)
-func mulInt32VecWithConst(dst []int32, src []int32, num int32) {
+// => |src[i] * num| <= ValidatorXWeight <= TotalValidatorWeight <= max(int64), as TotalValidatorWeight <= MaxTotalWeight
+func mulInt64VecWithConst(dst []int64, src []int64, num int64) {
 	for i := range len(src) {
 		dst[i] = src[i] * num
 	}
 }
 
 // normalize scales the values to the [-1, 1] range
-func normalizeInt32Vec(dst []int32, src []int32) {
+func normalizeInt64Vec(dst []int64, src []int64) {
 	for i := range len(src) {
 		if src[i] >= 0 {
 			dst[i] = 1
@@ -42,15 +42,15 @@ func normalizeInt32Vec(dst []int32, src []int32) {
 	}
 }
 
-func initInt32WithConst(num int32, length int) []int32 {
-	vec := make([]int32, length)
+func initInt64WithConst(num int64, length int) []int64 {
+	vec := make([]int64, length)
 	for i := range len(vec) {
 		vec[i] = num
 	}
 	return vec
 }
 
-func boolMaskInt32Vec(src []int32, predicate func(x int32) bool) []bool {
+func boolMaskInt64Vec(src []int64, predicate func(x int64) bool) []bool {
 	vec := make([]bool, len(src))
 	for i := range len(src) {
 		vec[i] = predicate(src[i])
@@ -23,7 +23,11 @@ type Genesis struct {
 }
 
 func (s *Store) ApplyGenesis(g *Genesis) error {
-	if ok, _ := s.table.LastDecidedState.Has([]byte(dsKey)); ok {
+	ok, err := s.table.LastDecidedState.Has([]byte(dsKey))
+	if err != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+	}
+	if ok {
 		return fmt.Errorf("genesis already applied")
 	}
 	return s.SwitchGenesis(g)
@@ -41,7 +45,8 @@ func (s *Store) SwitchGenesis(g *Genesis) error {
 	es.Validators = g.Validators
 	es.Epoch = g.Epoch
 	ds.LastDecidedFrame = consensus.FirstFrame - 1
-	s.SetEpochState(es)
-	s.SetLastDecidedState(ds)
-	return nil
+	if err := s.SetEpochState(es); err != nil {
+		return err
+	}
+	return s.SetLastDecidedState(ds)
 }
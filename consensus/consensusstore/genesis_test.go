@@ -15,7 +15,11 @@ func TestGenesis_Sucess(t *testing.T) {
 	if err := store.ApplyGenesis(&Genesis{Epoch: epoch, Validators: validators}); err != nil {
 		t.Fatal(err)
 	}
-	epochState, exists := store.get(store.table.EpochState, []byte(esKey), &EpochState{}).(*EpochState)
+	stored, err := store.get(store.table.EpochState, []byte(esKey), &EpochState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	epochState, exists := stored.(*EpochState)
 	if !exists {
 		t.Fatal("epoch state not set")
 	}
@@ -25,7 +29,11 @@ func TestGenesis_Sucess(t *testing.T) {
 	if want, got := epochState.Validators.Get(1), validators.Get(1); want != got {
 		t.Fatalf("expected set validator weight: %d, got: %d", want, got)
 	}
-	lastDecidedState, exists := store.get(store.table.LastDecidedState, []byte(dsKey), &LastDecidedState{}).(*LastDecidedState)
+	stored, err = store.get(store.table.LastDecidedState, []byte(dsKey), &LastDecidedState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastDecidedState, exists := stored.(*LastDecidedState)
 	if !exists {
 		t.Fatal("last decided state not set")
 	}
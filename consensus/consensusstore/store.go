@@ -12,6 +12,7 @@ package consensusstore
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/rlp"
 
@@ -23,15 +24,21 @@ import (
 )
 
 // Store is a abft persistent storage working over parent key-value database.
+// Storage failures are returned as errors wrapping consensus.ErrIntegrity.
+// The optional crit callback is called only on the paths which cannot return an error,
+// i.e. reading the epoch state before any genesis was applied or loaded.
 type Store struct {
 	GetEpochDB EpochDBProducer
 	cfg        StoreConfig
 	crit       func(error)
 
+	quorumThreshold consensus.QuorumThreshold
+
 	MainDB kvdb.Store
 	table  struct {
 		LastDecidedState kvdb.Store `table:"c"`
 		EpochState       kvdb.Store `table:"e"`
+		BlockLog         kvdb.Store `table:"b"`
 	}
 
 	cache struct {
@@ -39,12 +46,16 @@ type Store struct {
 		EpochState       *EpochState
 		FrameRoots       *simplewlru.Cache `cache:"-"` // store by pointer
 	}
+	// frameRootsMu guards the FrameRoots cache, which is mutated even by GetFrameRoots
+	frameRootsMu sync.Mutex
 
 	EpochDB    kvdb.Store
 	EpochTable struct {
-		Roots          kvdb.Store `table:"r"`
-		VectorIndex    kvdb.Store `table:"v"`
-		ConfirmedEvent kvdb.Store `table:"C"`
+		Roots            kvdb.Store `table:"r"`
+		VectorIndex      kvdb.Store `table:"v"`
+		ConfirmedEvent   kvdb.Store `table:"C"`
+		ConfirmedByFrame kvdb.Store `table:"F"`
+		ElectionState    kvdb.Store `table:"E"`
 	}
 }
 
@@ -55,6 +66,7 @@ var (
 type EpochDBProducer func(epoch consensus.Epoch) kvdb.Store
 
 // NewStore creates store over key-value db.
+// crit may be nil.
 func NewStore(mainDB kvdb.Store, getDB EpochDBProducer, crit func(error), cfg StoreConfig) *Store {
 	s := &Store{
 		GetEpochDB: getDB,
@@ -81,10 +93,7 @@ func NewMemStore() *Store {
 		return memorydb.New()
 	}
 	cfg := LiteStoreConfig()
-	crit := func(err error) {
-		panic(err)
-	}
-	return NewStore(memorydb.New(), getDb, crit, cfg)
+	return NewStore(memorydb.New(), getDb, nil, cfg)
 }
 
 // Close leaves underlying database.
@@ -138,38 +147,48 @@ func (s *Store) OpenEpochDB(n consensus.Epoch) error {
  */
 
 // set RLP value
-func (s *Store) set(table kvdb.Store, key []byte, val interface{}) {
+func (s *Store) set(table kvdb.Store, key []byte, val interface{}) error {
 	buf, err := rlp.EncodeToBytes(val)
 	if err != nil {
-		s.crit(err)
+		return consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "encoding %T: %w", val, err)
 	}
 
 	if err := table.Put(key, buf); err != nil {
-		s.crit(err)
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
 	}
+	return nil
 }
 
 // get RLP value
-func (s *Store) get(table kvdb.Store, key []byte, to interface{}) interface{} {
+func (s *Store) get(table kvdb.Store, key []byte, to interface{}) (interface{}, error) {
 	buf, err := table.Get(key)
 	if err != nil {
-		s.crit(err)
+		return nil, consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
 	}
 	if buf == nil {
-		return nil
+		return nil, nil
 	}
 
 	err = rlp.DecodeBytes(buf, to)
 	if err != nil {
+		return nil, consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "decoding %T: %w", to, err)
+	}
+	return to, nil
+}
+
+// fail handles an error on a path which isn't able to return it.
+// It's passed to the optional crit callback, and panics if the callback returns.
+func (s *Store) fail(err error) {
+	if s.crit != nil {
 		s.crit(err)
 	}
-	return to
+	panic(err)
 }
 
 func (s *Store) makeCache(weight uint, size int) *simplewlru.Cache {
 	cache, err := simplewlru.New(weight, size)
 	if err != nil {
-		s.crit(err)
+		s.fail(err)
 	}
 	return cache
 }
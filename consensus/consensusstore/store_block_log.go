@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusstore
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/kvdb"
+)
+
+const epochSize = 4
+
+// BlockKey identifies a block by its epoch and decided frame.
+type BlockKey struct {
+	Epoch consensus.Epoch
+	Frame consensus.Frame
+}
+
+func (k BlockKey) bytes() []byte {
+	return append(k.Epoch.Bytes(), k.Frame.Bytes()...)
+}
+
+func bytesToBlockKey(b []byte) BlockKey {
+	return BlockKey{
+		Epoch: consensus.BytesToEpoch(b[:epochSize]),
+		Frame: consensus.BytesToFrame(b[epochSize:]),
+	}
+}
+
+// BlockRecord is a historical record of a decided block.
+type BlockRecord struct {
+	Atropos  consensus.EventHash
+	Cheaters consensus.Cheaters
+	// ValidatorsHash is the hash of the validators of the block's epoch
+	ValidatorsHash consensus.Hash
+}
+
+// SetBlockRecord appends the block into the block log.
+func (s *Store) SetBlockRecord(key BlockKey, b *BlockRecord) error {
+	return s.set(s.table.BlockLog, key.bytes(), b)
+}
+
+// GetBlockRecord returns the block from the block log, or nil if there's none.
+func (s *Store) GetBlockRecord(key BlockKey) (*BlockRecord, error) {
+	w, err := s.get(s.table.BlockLog, key.bytes(), &BlockRecord{})
+	if err != nil || w == nil {
+		return nil, err
+	}
+	return w.(*BlockRecord), nil
+}
+
+// ForEachBlockRecord iterates over the block log in the order of keys, starting from the specified key.
+// Iteration stops if fn returns false.
+func (s *Store) ForEachBlockRecord(from BlockKey, fn func(BlockKey, *BlockRecord) bool) error {
+	it := s.table.BlockLog.NewIterator(nil, from.bytes())
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) != epochSize+frameSize {
+			return consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "block log: incorrect key len=%d", len(it.Key()))
+		}
+		b := &BlockRecord{}
+		if err := rlp.DecodeBytes(it.Value(), b); err != nil {
+			return consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "%w", err)
+		}
+		if !fn(bytesToBlockKey(it.Key()), b) {
+			break
+		}
+	}
+	if it.Error() != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", it.Error())
+	}
+	return nil
+}
+
+// PruneBlockLog deletes the blocks of the epochs before the specified one from the block log.
+func (s *Store) PruneBlockLog(before consensus.Epoch) error {
+	var keys [][]byte
+	err := s.ForEachBlockRecord(BlockKey{}, func(key BlockKey, _ *BlockRecord) bool {
+		if key.Epoch >= before {
+			return false
+		}
+		keys = append(keys, key.bytes())
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	batch := s.table.BlockLog.NewBatch()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensusstore
+
+import (
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+const elKey = "v"
+
+// ElectionState is a checkpoint of the election vote state, taken after a frame is decided.
+// It allows to restore the election without re-voting all the roots of the undecided frames.
+type ElectionState struct {
+	// FrameToDeliver is the lowest frame whose Atropos isn't delivered yet
+	FrameToDeliver consensus.Frame
+	// Votes are the vote contexts of the roots in the frames which aren't decided yet
+	Votes []RootVote
+	// Decided are the atropoi which are decided, but can't be delivered before the lower frames
+	Decided []DecidedAtropos
+	// VotedRoots are the roots of the Decided frames, which were voted already
+	VotedRoots consensus.EventHashes
+}
+
+// RootVote is the vote context of a root.
+type RootVote struct {
+	Frame                consensus.Frame
+	ValidatorID          consensus.ValidatorID
+	RootHash             consensus.EventHash
+	FrameToDeliverOffset consensus.Frame
+	// VoteMatrix contains signed votes in two's complement form, as RLP doesn't support signed integers
+	VoteMatrix []uint64
+}
+
+// DecidedAtropos is an Atropos decision which isn't delivered yet.
+type DecidedAtropos struct {
+	Frame       consensus.Frame
+	AtroposHash consensus.EventHash
+}
+
+// SetElectionState stores the election checkpoint of the current epoch.
+func (s *Store) SetElectionState(v *ElectionState) error {
+	return s.set(s.EpochTable.ElectionState, []byte(elKey), v)
+}
+
+// GetElectionState returns the election checkpoint of the current epoch, or nil if there's none.
+func (s *Store) GetElectionState() (*ElectionState, error) {
+	w, err := s.get(s.EpochTable.ElectionState, []byte(elKey), &ElectionState{})
+	if err != nil || w == nil {
+		return nil, err
+	}
+	return w.(*ElectionState), nil
+}
@@ -17,34 +17,73 @@ import (
 const esKey = "e"
 
 // SetEpochState stores epoch.
-func (s *Store) SetEpochState(e *EpochState) {
-	s.cache.EpochState = e
-	s.setEpochState([]byte(esKey), e)
+func (s *Store) SetEpochState(e *EpochState) error {
+	if err := s.setEpochState([]byte(esKey), e); err != nil {
+		return err
+	}
+	s.cache.EpochState = s.withQuorumThreshold(e)
+	return nil
 }
 
 // GetEpochState returns stored epoch.
+// It can fail only if the state isn't cached yet, use LoadEpochState to get the error instead.
 func (s *Store) GetEpochState() *EpochState {
+	e, err := s.LoadEpochState()
+	if err != nil {
+		s.fail(err)
+	}
+	return e
+}
+
+// LoadEpochState returns stored epoch, reading it from DB if it isn't cached yet.
+func (s *Store) LoadEpochState() (*EpochState, error) {
 	if s.cache.EpochState != nil {
-		return s.cache.EpochState
+		return s.cache.EpochState, nil
+	}
+	e, err := s.getEpochState([]byte(esKey))
+	if err != nil {
+		return nil, err
 	}
-	e := s.getEpochState([]byte(esKey))
 	if e == nil {
-		s.crit(ErrNoGenesis)
+		return nil, ErrNoGenesis
 	}
-	s.cache.EpochState = e
-	return e
+	s.cache.EpochState = s.withQuorumThreshold(e)
+	return s.cache.EpochState, nil
+}
+
+// SetQuorumThreshold sets the fault-tolerance threshold of the validators returned by the store.
+// The threshold isn't persisted, so it must be set before the epoch state is loaded.
+func (s *Store) SetQuorumThreshold(threshold consensus.QuorumThreshold) error {
+	if err := threshold.Validate(); err != nil {
+		return err
+	}
+	s.quorumThreshold = threshold
+	if s.cache.EpochState != nil {
+		s.cache.EpochState = s.withQuorumThreshold(s.cache.EpochState)
+	}
+	return nil
+}
+
+// withQuorumThreshold returns the epoch state with validators of the store's threshold
+func (s *Store) withQuorumThreshold(e *EpochState) *EpochState {
+	if e.Validators == nil || e.Validators.QuorumThreshold() == s.quorumThreshold {
+		return e
+	}
+	cp := *e
+	cp.Validators = e.Validators.WithQuorumThreshold(s.quorumThreshold)
+	return &cp
 }
 
-func (s *Store) setEpochState(key []byte, e *EpochState) {
-	s.set(s.table.EpochState, key, e)
+func (s *Store) setEpochState(key []byte, e *EpochState) error {
+	return s.set(s.table.EpochState, key, e)
 }
 
-func (s *Store) getEpochState(key []byte) *EpochState {
-	w, exists := s.get(s.table.EpochState, key, &EpochState{}).(*EpochState)
-	if !exists {
-		return nil
+func (s *Store) getEpochState(key []byte) (*EpochState, error) {
+	w, err := s.get(s.table.EpochState, key, &EpochState{})
+	if err != nil || w == nil {
+		return nil, err
 	}
-	return w
+	return w.(*EpochState), nil
 }
 
 // GetEpoch returns current epoch
@@ -11,29 +11,126 @@
 package consensusstore
 
 import (
+	"encoding/binary"
+
 	"github.com/0xsoniclabs/consensus/consensus"
 )
 
+const positionSize = 4
+
+// EventConfirmation describes the block which confirmed an event.
+type EventConfirmation struct {
+	// Frame is the decided frame of the block
+	Frame consensus.Frame
+	// Atropos is the Atropos of the block
+	Atropos consensus.EventHash
+	// Position is the ordinal position of the event among the events confirmed by the block
+	Position uint32
+}
+
+func (c *EventConfirmation) bytes() []byte {
+	buf := make([]byte, 0, frameSize+eventIDSize+positionSize)
+	buf = append(buf, c.Frame.Bytes()...)
+	buf = append(buf, c.Atropos.Bytes()...)
+	return binary.BigEndian.AppendUint32(buf, c.Position)
+}
+
+func confirmedByFrameKey(frame consensus.Frame, position uint32) []byte {
+	return binary.BigEndian.AppendUint32(frame.Bytes(), position)
+}
+
 // SetEventConfirmedOn stores confirmed event ctype.
-func (s *Store) SetEventConfirmedOn(e consensus.EventHash, on consensus.Frame) {
+// Unlike SetEventConfirmation, it records only the frame.
+func (s *Store) SetEventConfirmedOn(e consensus.EventHash, on consensus.Frame) error {
 	key := e.Bytes()
 
 	if err := s.EpochTable.ConfirmedEvent.Put(key, on.Bytes()); err != nil {
-		s.crit(err)
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+	}
+	return nil
+}
+
+// SetEventConfirmation stores the block and the position which confirmed the event.
+func (s *Store) SetEventConfirmation(e consensus.EventHash, c EventConfirmation) error {
+	if err := s.EpochTable.ConfirmedEvent.Put(e.Bytes(), c.bytes()); err != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
+	}
+	if err := s.EpochTable.ConfirmedByFrame.Put(confirmedByFrameKey(c.Frame, c.Position), e.Bytes()); err != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
 	}
+	return nil
 }
 
 // GetEventConfirmedOn returns confirmed event ctype.
-func (s *Store) GetEventConfirmedOn(e consensus.EventHash) consensus.Frame {
+func (s *Store) GetEventConfirmedOn(e consensus.EventHash) (consensus.Frame, error) {
+	c, err := s.GetEventConfirmation(e)
+	if err != nil || c == nil {
+		return 0, err
+	}
+	return c.Frame, nil
+}
+
+// GetEventConfirmation returns the block and the position which confirmed the event, or nil if it isn't confirmed.
+// Only the Frame is set for the events confirmed by SetEventConfirmedOn.
+func (s *Store) GetEventConfirmation(e consensus.EventHash) (*EventConfirmation, error) {
 	key := e.Bytes()
 
 	buf, err := s.EpochTable.ConfirmedEvent.Get(key)
 	if err != nil {
-		s.crit(err)
+		return nil, consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
 	}
-	if buf == nil {
-		return 0
+	switch len(buf) {
+	case 0:
+		return nil, nil
+	case frameSize:
+		return &EventConfirmation{Frame: consensus.BytesToFrame(buf)}, nil
+	case frameSize + eventIDSize + positionSize:
+		return &EventConfirmation{
+			Frame:    consensus.BytesToFrame(buf[:frameSize]),
+			Atropos:  consensus.BytesToEvent(buf[frameSize : frameSize+eventIDSize]),
+			Position: binary.BigEndian.Uint32(buf[frameSize+eventIDSize:]),
+		}, nil
+	default:
+		return nil, consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "confirmed event table: incorrect value len=%d", len(buf))
 	}
+}
 
-	return consensus.BytesToFrame(buf)
+// ForEachConfirmedEvent iterates over the events confirmed by the block of the frame, in the order of their positions.
+// Iteration stops if fn returns false.
+func (s *Store) ForEachConfirmedEvent(frame consensus.Frame, fn func(position uint32, e consensus.EventHash) bool) error {
+	it := s.EpochTable.ConfirmedByFrame.NewIterator(frame.Bytes(), nil)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != frameSize+positionSize || len(it.Value()) != eventIDSize {
+			return consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "confirmed by frame table: incorrect key len=%d, value len=%d", len(key), len(it.Value()))
+		}
+		if !fn(binary.BigEndian.Uint32(key[frameSize:]), consensus.BytesToEvent(it.Value())) {
+			break
+		}
+	}
+	if it.Error() != nil {
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", it.Error())
+	}
+	return nil
+}
+
+// GetConfirmedEvents returns the events confirmed by the block of the frame, in the order of their positions.
+func (s *Store) GetConfirmedEvents(frame consensus.Frame) (consensus.EventHashes, error) {
+	var res consensus.EventHashes
+	err := s.ForEachConfirmedEvent(frame, func(_ uint32, e consensus.EventHash) bool {
+		res = append(res, e)
+		return true
+	})
+	return res, err
+}
+
+// GetEventsConfirmedBy returns the events confirmed by the Atropos, in the order of their positions.
+// Returns nil if the event isn't an Atropos of a block.
+func (s *Store) GetEventsConfirmedBy(atropos consensus.EventHash) (consensus.EventHashes, error) {
+	c, err := s.GetEventConfirmation(atropos)
+	if err != nil || c == nil || c.Atropos != atropos {
+		return nil, err
+	}
+	return s.GetConfirmedEvents(c.Frame)
 }
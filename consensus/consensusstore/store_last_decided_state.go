@@ -15,27 +15,40 @@ import "github.com/0xsoniclabs/consensus/consensus"
 const dsKey = "d"
 
 // SetLastDecidedState save LastDecidedState.
-// LastDecidedState is seldom read; so no cache.
-func (s *Store) SetLastDecidedState(v *LastDecidedState) {
+func (s *Store) SetLastDecidedState(v *LastDecidedState) error {
+	if err := s.set(s.table.LastDecidedState, []byte(dsKey), v); err != nil {
+		return err
+	}
 	s.cache.LastDecidedState = v
-
-	s.set(s.table.LastDecidedState, []byte(dsKey), v)
+	return nil
 }
 
 // GetLastDecidedState returns stored LastDecidedState.
-// State is seldom read; so no cache.
+// It can fail only if the state isn't cached yet, use LoadLastDecidedState to get the error instead.
 func (s *Store) GetLastDecidedState() *LastDecidedState {
+	v, err := s.LoadLastDecidedState()
+	if err != nil {
+		s.fail(err)
+	}
+	return v
+}
+
+// LoadLastDecidedState returns stored LastDecidedState, reading it from DB if it isn't cached yet.
+func (s *Store) LoadLastDecidedState() (*LastDecidedState, error) {
 	if s.cache.LastDecidedState != nil {
-		return s.cache.LastDecidedState
+		return s.cache.LastDecidedState, nil
 	}
 
-	w, exists := s.get(s.table.LastDecidedState, []byte(dsKey), &LastDecidedState{}).(*LastDecidedState)
-	if !exists {
-		s.crit(ErrNoGenesis)
+	w, err := s.get(s.table.LastDecidedState, []byte(dsKey), &LastDecidedState{})
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, ErrNoGenesis
 	}
 
-	s.cache.LastDecidedState = w
-	return w
+	s.cache.LastDecidedState = w.(*LastDecidedState)
+	return s.cache.LastDecidedState, nil
 }
 
 func (s *Store) GetLastDecidedFrame() consensus.Frame {
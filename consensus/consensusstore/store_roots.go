@@ -12,7 +12,6 @@ package consensusstore
 
 import (
 	"bytes"
-	"fmt"
 
 	"github.com/0xsoniclabs/consensus/consensus"
 )
@@ -39,33 +38,40 @@ func rootRecordKey(frame consensus.Frame, root *RootDescriptor) []byte {
 
 // AddRoot stores the new root
 // Not safe for concurrent use due to the complex mutable cache!
-func (s *Store) AddRoot(root consensus.Event) {
-	s.addRoot(root, root.Frame())
+func (s *Store) AddRoot(root consensus.Event) error {
+	return s.addRoot(root, root.Frame())
 }
 
-func (s *Store) addRoot(root consensus.Event, frame consensus.Frame) {
+func (s *Store) addRoot(root consensus.Event, frame consensus.Frame) error {
 	r := RootDescriptor{
 		ValidatorID: root.Creator(),
 		RootHash:    root.ID(),
 	}
 
 	if err := s.EpochTable.Roots.Put(rootRecordKey(frame, &r), []byte{}); err != nil {
-		s.crit(err)
+		return consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err)
 	}
 
 	// Add to cache.
+	s.frameRootsMu.Lock()
+	defer s.frameRootsMu.Unlock()
 	if c, ok := s.cache.FrameRoots.Get(frame); ok {
 		rr := c.([]RootDescriptor)
 		rr = append(rr, r)
 		s.cache.FrameRoots.Add(frame, rr, uint(len(rr)))
 	}
+	return nil
 }
 
 // GetFrameRoots returns all the roots in the specified frame
-// Not safe for concurrent use due to the complex mutable cache!
-func (s *Store) GetFrameRoots(frame consensus.Frame) []RootDescriptor {
-	if rr, ok := s.cache.FrameRoots.Get(frame); ok {
-		return rr.([]RootDescriptor)
+// Safe for concurrent use with other readers, but not with AddRoot.
+// The returned slice must not be modified.
+func (s *Store) GetFrameRoots(frame consensus.Frame) ([]RootDescriptor, error) {
+	s.frameRootsMu.Lock()
+	rr, ok := s.cache.FrameRoots.Get(frame)
+	s.frameRootsMu.Unlock()
+	if ok {
+		return rr.([]RootDescriptor), nil
 	}
 	roots := make([]RootDescriptor, 0, 100)
 	it := s.EpochTable.Roots.NewIterator(frame.Bytes(), nil)
@@ -73,7 +79,7 @@ func (s *Store) GetFrameRoots(frame consensus.Frame) []RootDescriptor {
 	for it.Next() {
 		key := it.Key()
 		if len(key) != frameSize+validatorIDSize+eventIDSize {
-			s.crit(fmt.Errorf("roots table: incorrect key len=%d", len(key)))
+			return nil, consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "roots table: incorrect key len=%d", len(key))
 		}
 
 		r := RootDescriptor{
@@ -83,10 +89,12 @@ func (s *Store) GetFrameRoots(frame consensus.Frame) []RootDescriptor {
 		roots = append(roots, r)
 	}
 	if it.Error() != nil {
-		s.crit(it.Error())
+		return nil, consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", it.Error())
 	}
 
+	s.frameRootsMu.Lock()
 	s.cache.FrameRoots.Add(frame, roots, uint(len(roots)))
+	s.frameRootsMu.Unlock()
 
-	return roots
+	return roots, nil
 }
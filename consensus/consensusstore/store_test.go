@@ -1,7 +1,9 @@
 package consensusstore
 
 import (
+	"errors"
 	"math/rand"
+	"reflect"
 	"slices"
 	"testing"
 
@@ -29,7 +31,10 @@ func TestStore_FrameRootPersisting(t *testing.T) {
 	}
 	rand.Shuffle(len(retrievalOrder), func(i, j int) { retrievalOrder[i], retrievalOrder[j] = retrievalOrder[j], retrievalOrder[i] })
 	for _, frame := range retrievalOrder {
-		frameRoots := store.GetFrameRoots(consensus.Frame(frame))
+		frameRoots, err := store.GetFrameRoots(consensus.Frame(frame))
+		if err != nil {
+			t.Fatal(err)
+		}
 		if want, got := len(roots[frame]), len(frameRoots); want != got {
 			t.Fatalf("incorrect number of roots retrieved for frame %d, expected: %d, got: %d", frame, want, got)
 		}
@@ -42,6 +47,161 @@ func TestStore_FrameRootPersisting(t *testing.T) {
 	}
 }
 
+func TestStore_CorruptedRootsAreReported(t *testing.T) {
+	store := NewMemStore()
+	if err := store.OpenEpochDB(1); err != nil {
+		t.Fatal(err)
+	}
+	frame := consensus.Frame(3)
+	if err := store.EpochTable.Roots.Put(append(frame.Bytes(), 1, 2, 3), []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetFrameRoots(frame); !errors.Is(err, consensus.ErrCorruptedRecord) {
+		t.Fatalf("expected corrupted record error, got: %v", err)
+	}
+}
+
+func TestStore_NoGenesisIsReported(t *testing.T) {
+	store := NewMemStore()
+	if _, err := store.LoadEpochState(); !errors.Is(err, ErrNoGenesis) {
+		t.Fatalf("expected no genesis error, got: %v", err)
+	}
+	if _, err := store.LoadLastDecidedState(); !errors.Is(err, ErrNoGenesis) {
+		t.Fatalf("expected no genesis error, got: %v", err)
+	}
+}
+
+func TestStore_ElectionStatePersisting(t *testing.T) {
+	store := NewMemStore()
+	if err := store.OpenEpochDB(1); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := store.GetElectionState(); err != nil || state != nil {
+		t.Fatalf("expected no election state, got: %v, %v", state, err)
+	}
+	minusOne := int64(-1)
+	want := &ElectionState{
+		FrameToDeliver: 4,
+		Votes: []RootVote{
+			{Frame: 4, ValidatorID: 1, RootHash: consensus.EventHash{1}, FrameToDeliverOffset: 4, VoteMatrix: []uint64{}},
+			{Frame: 5, ValidatorID: 2, RootHash: consensus.EventHash{2}, FrameToDeliverOffset: 3, VoteMatrix: []uint64{uint64(minusOne), 1 << 40}},
+		},
+		Decided:    []DecidedAtropos{{Frame: 6, AtroposHash: consensus.EventHash{3}}},
+		VotedRoots: consensus.EventHashes{{3}, {4}},
+	}
+	if err := store.SetElectionState(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetElectionState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("incorrect election state retrieved. expected: %v, got: %v", want, got)
+	}
+	if int64(got.Votes[1].VoteMatrix[0]) != -1 {
+		t.Fatalf("expected negative vote to be preserved, got: %d", int64(got.Votes[1].VoteMatrix[0]))
+	}
+}
+
+func TestStore_EventConfirmationPersisting(t *testing.T) {
+	store := NewMemStore()
+	if err := store.OpenEpochDB(1); err != nil {
+		t.Fatal(err)
+	}
+	atropos := consensus.EventHash{1}
+	events := consensus.EventHashes{atropos, {2}, {3}}
+	for i, e := range events {
+		if err := store.SetEventConfirmation(e, EventConfirmation{Frame: 5, Atropos: atropos, Position: uint32(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// frame-only record
+	if err := store.SetEventConfirmedOn(consensus.EventHash{4}, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := store.GetEventConfirmation(events[2]); err != nil || *got != (EventConfirmation{Frame: 5, Atropos: atropos, Position: 2}) {
+		t.Fatalf("incorrect event confirmation retrieved: %v, %v", got, err)
+	}
+	if got, err := store.GetEventConfirmation(consensus.EventHash{4}); err != nil || *got != (EventConfirmation{Frame: 6}) {
+		t.Fatalf("incorrect frame-only event confirmation retrieved: %v, %v", got, err)
+	}
+	if got, err := store.GetEventConfirmation(consensus.EventHash{5}); err != nil || got != nil {
+		t.Fatalf("expected no event confirmation, got: %v, %v", got, err)
+	}
+	if got, err := store.GetEventsConfirmedBy(atropos); err != nil || !slices.Equal(events, got) {
+		t.Fatalf("incorrect events confirmed by atropos. expected: %v, got: %v, %v", events, got, err)
+	}
+	if got, err := store.GetEventsConfirmedBy(events[1]); err != nil || got != nil {
+		t.Fatalf("expected no events confirmed by non-atropos, got: %v, %v", got, err)
+	}
+
+	var first consensus.EventHashes
+	err := store.ForEachConfirmedEvent(5, func(position uint32, e consensus.EventHash) bool {
+		first = append(first, e)
+		return position < 1
+	})
+	if err != nil || !slices.Equal(events[:2], first) {
+		t.Fatalf("incorrect iteration. expected: %v, got: %v, %v", events[:2], first, err)
+	}
+
+	if err := store.EpochTable.ConfirmedEvent.Put(events[0].Bytes(), []byte{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetEventConfirmation(events[0]); !errors.Is(err, consensus.ErrCorruptedRecord) {
+		t.Fatalf("expected corrupted record error, got: %v", err)
+	}
+}
+
+func TestStore_BlockLog(t *testing.T) {
+	store := NewMemStore()
+	var keys []BlockKey
+	for epoch := consensus.Epoch(1); epoch <= 3; epoch++ {
+		for frame := consensus.Frame(1); frame <= 300; frame++ {
+			key := BlockKey{Epoch: epoch, Frame: frame}
+			record := &BlockRecord{
+				Atropos:        consensus.EventHash{byte(epoch), byte(frame)},
+				Cheaters:       consensus.Cheaters{consensus.ValidatorID(frame)},
+				ValidatorsHash: consensus.Hash{byte(epoch)},
+			}
+			if err := store.SetBlockRecord(key, record); err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key)
+		}
+	}
+	want := &BlockRecord{Atropos: consensus.EventHash{2, 7}, Cheaters: consensus.Cheaters{7}, ValidatorsHash: consensus.Hash{2}}
+	if got, err := store.GetBlockRecord(BlockKey{2, 7}); err != nil || !reflect.DeepEqual(want, got) {
+		t.Fatalf("incorrect block record retrieved. expected: %v, got: %v, %v", want, got, err)
+	}
+	if got, err := store.GetBlockRecord(BlockKey{4, 1}); err != nil || got != nil {
+		t.Fatalf("expected no block record, got: %v, %v", got, err)
+	}
+
+	// iteration is ordered by epoch, then by frame
+	var got []BlockKey
+	err := store.ForEachBlockRecord(BlockKey{2, 299}, func(key BlockKey, _ *BlockRecord) bool {
+		got = append(got, key)
+		return len(got) < 3
+	})
+	if err != nil || !slices.Equal(keys[598:601], got) {
+		t.Fatalf("incorrect iteration. expected: %v, got: %v, %v", keys[598:601], got, err)
+	}
+
+	if err := store.PruneBlockLog(3); err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	err = store.ForEachBlockRecord(BlockKey{}, func(key BlockKey, _ *BlockRecord) bool {
+		got = append(got, key)
+		return true
+	})
+	if err != nil || !slices.Equal(keys[600:], got) {
+		t.Fatalf("incorrect blocks after pruning: %d, %v", len(got), err)
+	}
+}
+
 func TestStore_Close(t *testing.T) {
 	store := NewMemStore()
 	populateWithEpochStates(store)
@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// Adversary is a byzantine behaviour of a validator, the zero value is an honest validator.
+// The behaviours may be combined.
+type Adversary struct {
+	// Forks is the max number of forks created by the validator, i.e. events whose self-parent isn't the last own event
+	Forks int
+	// Withhold is the number of steps by which the delivery of the validator's events to the other nodes is delayed
+	Withhold int
+	// Peers, if not nil, are the only validators whose events are referenced by the validator's events
+	Peers []consensus.ValidatorID
+}
+
+// Honest returns true if the validator doesn't misbehave.
+func (a Adversary) Honest() bool {
+	return a.Forks == 0 && a.Withhold == 0 && a.Peers == nil
+}
+
+// Partition splits the nodes into the Group and the rest of the nodes in the steps, or the time of Network, [From, To).
+// The events created on the one side aren't delivered to the other side until the partition heals.
+type Partition struct {
+	From, To int
+	Group    []consensus.ValidatorID
+}
+
+func (p *Partition) separates(step int, a, b consensus.ValidatorID) bool {
+	if step < p.From || step >= p.To {
+		return false
+	}
+	inGroup := func(v consensus.ValidatorID) bool {
+		for _, member := range p.Group {
+			if member == v {
+				return true
+			}
+		}
+		return false
+	}
+	return inGroup(a) != inGroup(b)
+}
+
+// WeightedGroup returns the least prefix of the validators, in the order of SortedIDs, which holds at least the weight.
+// It's the Group of a stake-weighted Partition.
+func WeightedGroup(validators *consensus.Validators, weight consensus.Weight) []consensus.ValidatorID {
+	var (
+		group []consensus.ValidatorID
+		sum   consensus.Weight
+	)
+	for _, v := range validators.SortedIDs() {
+		if sum >= weight {
+			break
+		}
+		group = append(group, v)
+		sum += validators.Get(v)
+	}
+	return group
+}
+
+// ByzantineConfig is the configuration of ForEachByzantineEvent.
+type ByzantineConfig struct {
+	// Steps is the number of the created events, the nodes create the events in turn
+	Steps int
+	// Parents is the max number of parents of an event
+	Parents int
+	// MaxDelay is the max number of steps by which the delivery of an event is delayed, the delay is random
+	MaxDelay int
+	// Adversaries are the byzantine validators, the rest of the validators are honest
+	Adversaries map[consensus.ValidatorID]Adversary
+	Partitions  []Partition
+}
+
+// ForEachByzantine are the callbacks of ForEachByzantineEvent.
+type ForEachByzantine struct {
+	// Build is called on the creator of the event, the event is dropped if it returns an error
+	Build func(creator consensus.ValidatorID, e consensus.MutableEvent, name string) error
+	// Deliver is called when the node receives the event, after all the parents are delivered to the node.
+	// The creator receives its own event right after it's built.
+	Deliver func(node consensus.ValidatorID, e consensus.Event, name string)
+}
+
+// nodeView is a local view of the DAG of a node
+type nodeView struct {
+	id        consensus.ValidatorID
+	adversary Adversary
+	own       consensus.Events
+	forks     int
+	known     consensus.EventHashSet
+	// latest are the latest known events of the validators
+	latest map[consensus.ValidatorID]consensus.Event
+	// pending are the received events whose parents aren't delivered yet
+	pending consensus.Events
+}
+
+// byzantineDelivery is an event in transit
+type byzantineDelivery struct {
+	at int
+	to *nodeView
+	e  *TestEvent
+}
+
+// ForEachByzantineEvent generates random events like ForEachRandEvent, but every node creates its events on its
+// own view of the DAG, and the events are delivered to the other nodes with random delays, so that the nodes
+// observe different DAGs. The adversaries create forks, withhold their events, and reference only a subset of
+// the peers, and the partitions cut the delivery between the groups of nodes.
+// After the last step all the partitions heal and all the events are delivered to all the nodes.
+// Result:
+//   - callbacks are called for each new event and for each delivery;
+//   - events maps node address to array of its events;
+func ForEachByzantineEvent(
+	nodes []consensus.ValidatorID,
+	config ByzantineConfig,
+	r *rand.Rand,
+	callback ForEachByzantine,
+) (
+	events map[consensus.ValidatorID]consensus.Events,
+) {
+	if r == nil {
+		// fixed seed
+		r = rand.New(rand.NewSource(0)) // nolint:gosec
+	}
+	events = make(map[consensus.ValidatorID]consensus.Events, len(nodes))
+	views := make([]*nodeView, len(nodes))
+	for i, id := range nodes {
+		views[i] = &nodeView{
+			id:        id,
+			adversary: config.Adversaries[id],
+			known:     consensus.EventHashSet{},
+			latest:    map[consensus.ValidatorID]consensus.Event{},
+		}
+	}
+	names := map[consensus.EventHash]string{}
+	deliver := func(node *nodeView, e consensus.Event) {
+		if callback.Deliver != nil {
+			callback.Deliver(node.id, e, names[e.ID()])
+		}
+	}
+
+	var transit []*byzantineDelivery
+	flush := func(step int, heal bool) {
+		sort.SliceStable(transit, func(i, j int) bool {
+			return transit[i].at < transit[j].at
+		})
+		var delayed []*byzantineDelivery
+		for _, d := range transit {
+			if !heal && (d.at > step || config.separated(step, d.e.Creator(), d.to.id)) {
+				delayed = append(delayed, d)
+				continue
+			}
+			d.to.receive(d.e, deliver)
+		}
+		transit = delayed
+	}
+
+	for step := 0; step < config.Steps; step++ {
+		flush(step, false)
+
+		self := step % len(nodes)
+		creator := views[self]
+		e := creator.next(config.Parents, r)
+		e.Name = fmt.Sprintf("%s%03d", string('a'+rune(self)), len(creator.own))
+		if callback.Build != nil {
+			if err := callback.Build(creator.id, e, e.Name); err != nil {
+				continue
+			}
+		}
+		e.SetID(CalcHashForTestEvent(e))
+		names[e.ID()] = e.Name
+		consensus.SetEventName(e.ID(), e.Name)
+		creator.own = append(creator.own, e)
+		events[creator.id] = append(events[creator.id], e)
+		creator.receive(e, deliver)
+
+		for _, to := range views {
+			if to == creator {
+				continue
+			}
+			transit = append(transit, &byzantineDelivery{
+				at: step + 1 + creator.adversary.Withhold + r.Intn(config.MaxDelay+1),
+				to: to,
+				e:  e,
+			})
+		}
+	}
+	flush(config.Steps, true)
+
+	return
+}
+
+func (c *ByzantineConfig) separated(step int, a, b consensus.ValidatorID) bool {
+	for i := range c.Partitions {
+		if c.Partitions[i].separates(step, a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// next makes a new event of the node on its view
+func (n *nodeView) next(parentCount int, r *rand.Rand) *TestEvent {
+	e := &TestEvent{}
+	e.SetCreator(n.id)
+	e.SetParents(consensus.EventHashes{})
+
+	// first parent is the last own event, or a random own event if it's a fork
+	var selfParent consensus.Event
+	if len(n.own) > 0 {
+		selfParent = n.own[len(n.own)-1]
+		if n.forks < n.adversary.Forks && len(n.own) > 1 && r.Intn(2) == 0 {
+			selfParent = n.own[r.Intn(len(n.own)-1)]
+			if r.Intn(len(n.own)) == 0 {
+				selfParent = nil
+			}
+			n.forks++
+		}
+	}
+	if selfParent == nil {
+		e.SetSeq(1)
+		e.SetLamport(1)
+		return e
+	}
+	e.SetSeq(selfParent.Seq() + 1)
+	e.SetLamport(selfParent.Lamport() + 1)
+	e.AddParent(selfParent.ID())
+
+	// other parents are the latest known events of the random peers
+	peers := append([]consensus.ValidatorID{}, n.adversary.Peers...)
+	if n.adversary.Peers == nil {
+		for v := range n.latest {
+			peers = append(peers, v)
+		}
+		sort.Slice(peers, func(i, j int) bool {
+			return peers[i] < peers[j]
+		})
+	}
+	r.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	for _, peer := range peers {
+		if len(e.Parents()) >= parentCount {
+			break
+		}
+		parent, ok := n.latest[peer]
+		if !ok || peer == n.id {
+			continue
+		}
+		e.AddParent(parent.ID())
+		if e.Lamport() <= parent.Lamport() {
+			e.SetLamport(parent.Lamport() + 1)
+		}
+	}
+	return e
+}
+
+// receive delivers the event once all its parents are delivered, along with the pending events which become deliverable
+func (n *nodeView) receive(e consensus.Event, deliver func(*nodeView, consensus.Event)) {
+	if n.known.Contains(e.ID()) {
+		return
+	}
+	n.pending = append(n.pending, e)
+	for progress := true; progress; {
+		progress = false
+		pending := n.pending[:0]
+		for _, p := range n.pending {
+			if n.known.Contains(p.ID()) {
+				continue
+			}
+			if !n.knows(p.Parents()) {
+				pending = append(pending, p)
+				continue
+			}
+			n.known.Add(p.ID())
+			if latest, ok := n.latest[p.Creator()]; !ok || latest.Seq() < p.Seq() {
+				n.latest[p.Creator()] = p
+			}
+			deliver(n, p)
+			progress = true
+		}
+		n.pending = pending
+	}
+}
+
+func (n *nodeView) knows(events consensus.EventHashes) bool {
+	for _, e := range events {
+		if !n.known.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+func TestForEachByzantineEvent(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := GenNodes(5)
+	config := ByzantineConfig{
+		Steps:    500,
+		Parents:  3,
+		MaxDelay: 5,
+		Adversaries: map[consensus.ValidatorID]Adversary{
+			nodes[0]: {Forks: 3},
+			nodes[1]: {Withhold: 20},
+			nodes[2]: {Peers: nodes[3:4]},
+		},
+		Partitions: []Partition{{From: 100, To: 200, Group: nodes[3:]}},
+	}
+
+	step := 0
+	createdAt := map[string]int{}
+	delivered := map[consensus.ValidatorID]consensus.EventHashSet{}
+	for _, v := range nodes {
+		delivered[v] = consensus.EventHashSet{}
+	}
+	inGroup := func(v consensus.ValidatorID) bool {
+		return v == nodes[3] || v == nodes[4]
+	}
+	events := ForEachByzantineEvent(nodes, config, rand.New(rand.NewSource(0)), ForEachByzantine{ // nolint:gosec
+		Build: func(creator consensus.ValidatorID, e consensus.MutableEvent, name string) error {
+			createdAt[name] = step
+			step++
+			return nil
+		},
+		Deliver: func(node consensus.ValidatorID, e consensus.Event, name string) {
+			for _, p := range e.Parents() {
+				assertar.True(delivered[node].Contains(p), "parent of %s isn't delivered to %d", name, node)
+			}
+			delivered[node].Add(e.ID())
+			if node == e.Creator() {
+				return
+			}
+			created := createdAt[name]
+			// the events created during the partition don't cross it until it heals
+			if created >= 100 && created < 200 && inGroup(node) != inGroup(e.Creator()) {
+				assertar.GreaterOrEqual(step, 200, "%s to %d on step %d", name, node, step)
+			}
+			// the withheld events are late, unless they are flushed after the last step
+			if e.Creator() == nodes[1] && step < config.Steps {
+				assertar.Greater(step, created+20, "%s to %d on step %d", name, node, step)
+			}
+		},
+	})
+	byID := map[consensus.EventHash]consensus.Event{}
+	for _, ee := range events {
+		for _, e := range ee {
+			byID[e.ID()] = e
+		}
+	}
+
+	// all the events are delivered to all the nodes
+	all := consensus.EventHashSet{}
+	forks := 0
+	for creator, ee := range events {
+		seqs := map[consensus.Seq]bool{}
+		for _, e := range ee {
+			all.Add(e.ID())
+			if seqs[e.Seq()] {
+				forks++
+			}
+			seqs[e.Seq()] = true
+			if creator == nodes[2] && len(e.Parents()) > 1 {
+				for _, p := range e.Parents()[1:] {
+					assertar.Equal(nodes[3], byID[p].Creator())
+				}
+			}
+		}
+	}
+	assertar.Len(all, config.Steps)
+	assertar.NotZero(forks)
+	for _, v := range nodes {
+		assertar.Equal(all, delivered[v])
+	}
+}
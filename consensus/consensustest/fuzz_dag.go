@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"fmt"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+const (
+	// MaxFuzzValidators is the max number of validators of a FuzzDAG
+	MaxFuzzValidators = 8
+	// MaxFuzzEvents is the max number of events of a FuzzDAG, the rest of the input is ignored
+	MaxFuzzEvents = 256
+
+	// fuzzForkFlag marks the self-parent byte of a fork
+	fuzzForkFlag = 0x80
+)
+
+// FuzzDAG is a DAG of events decoded from an arbitrary byte stream, to feed the fuzz tests.
+// Every input decodes into a valid parent-closed DAG. The encoding is:
+//   - byte 0 is the number of validators, from 2 to MaxFuzzValidators;
+//   - the next byte per validator is its weight, from 1 to 4;
+//   - the next 3 bytes per event are the creator, the self-parent and the bitmask of the other parents.
+//
+// The self-parent is the last event of the creator, unless the creator is one of the Cheaters and the byte has
+// the fork flag, then it's the event of the creator chosen by the rest of the byte, or none.
+// The other parents are the last events of the validators in the bitmask.
+type FuzzDAG struct {
+	Nodes   []consensus.ValidatorID
+	Weights []consensus.Weight
+	// Cheaters are the validators which may create forks, their total weight is less than 1/3W
+	Cheaters []consensus.ValidatorID
+
+	events []byte
+}
+
+// DecodeFuzzDAG decodes the validators of the DAG, the events are decoded by ForEach.
+// The missing bytes are decoded as zeros.
+func DecodeFuzzDAG(data []byte) *FuzzDAG {
+	next := func() byte {
+		if len(data) == 0 {
+			return 0
+		}
+		b := data[0]
+		data = data[1:]
+		return b
+	}
+	d := &FuzzDAG{}
+	count := 2 + int(next())%(MaxFuzzValidators-1)
+	total := consensus.Weight(0)
+	for i := 0; i < count; i++ {
+		d.Nodes = append(d.Nodes, consensus.ValidatorID(i+1))
+		d.Weights = append(d.Weights, consensus.Weight(1+next()%4))
+		total += d.Weights[i]
+	}
+	cheatersWeight := consensus.Weight(0)
+	for i, w := range d.Weights {
+		if 3*(cheatersWeight+w) >= total {
+			break
+		}
+		cheatersWeight += w
+		d.Cheaters = append(d.Cheaters, d.Nodes[i])
+	}
+	d.events = data
+	return d
+}
+
+// ForEach decodes the events of the DAG.
+// Result:
+//   - callbacks are called for each new event, the event is dropped if Build returns an error;
+//   - events maps node address to array of its events;
+func (d *FuzzDAG) ForEach(callback ForEachEvent) (events map[consensus.ValidatorID]consensus.Events) {
+	events = make(map[consensus.ValidatorID]consensus.Events, len(d.Nodes))
+	cheaters := make(map[consensus.ValidatorID]bool, len(d.Cheaters))
+	for _, cheater := range d.Cheaters {
+		cheaters[cheater] = true
+	}
+
+	data := d.events
+	for i := 0; len(data) >= 3 && i < MaxFuzzEvents; i, data = i+1, data[3:] {
+		self := int(data[0]) % len(d.Nodes)
+		creator := d.Nodes[self]
+		own := events[creator]
+
+		e := &TestEvent{}
+		e.SetCreator(creator)
+		e.SetParents(consensus.EventHashes{})
+		// first parent is the last creator's event, a random creator's event or empty hash if it's a fork
+		var parent consensus.Event
+		if len(own) > 0 {
+			parent = own[len(own)-1]
+		}
+		if data[1]&fuzzForkFlag != 0 && cheaters[creator] {
+			parent = nil
+			if j := int(data[1]&^fuzzForkFlag) % (len(own) + 1); j < len(own) {
+				parent = own[j]
+			}
+		}
+		if parent == nil {
+			e.SetSeq(1)
+			e.SetLamport(1)
+		} else {
+			e.SetSeq(parent.Seq() + 1)
+			e.AddParent(parent.ID())
+			e.SetLamport(parent.Lamport() + 1)
+			// other parents are the last events of the validators in the bitmask
+			for j, other := range d.Nodes {
+				if j == self || data[2]&(1<<j) == 0 || len(events[other]) == 0 {
+					continue
+				}
+				parent := events[other][len(events[other])-1]
+				e.AddParent(parent.ID())
+				if e.Lamport() <= parent.Lamport() {
+					e.SetLamport(parent.Lamport() + 1)
+				}
+			}
+		}
+		// the name is unique, so the equal events of a cheater are distinct forks
+		e.Name = fmt.Sprintf("%s%03d", string('a'+rune(self)), len(own))
+		if callback.Build != nil {
+			if err := callback.Build(e, e.Name); err != nil {
+				continue
+			}
+		}
+		e.SetID(CalcHashForTestEvent(e))
+		consensus.SetEventName(e.ID(), e.Name)
+		events[creator] = append(own, e)
+		if callback.Process != nil {
+			callback.Process(e, e.Name)
+		}
+	}
+
+	return
+}
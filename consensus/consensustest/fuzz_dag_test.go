@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+func TestDecodeFuzzDAG(t *testing.T) {
+	assertar := assert.New(t)
+
+	dag := DecodeFuzzDAG(nil)
+	assertar.Len(dag.Nodes, 2)
+	assertar.Equal([]consensus.Weight{1, 1}, dag.Weights)
+	assertar.Empty(dag.Cheaters)
+	assertar.Empty(dag.ForEach(ForEachEvent{}))
+
+	// 4 validators of the weights 1, 2, 3, 4, the first two of them may fork as 3*3 < 10
+	dag = DecodeFuzzDAG([]byte{2, 0, 1, 2, 3})
+	assertar.Equal([]consensus.ValidatorID{1, 2, 3, 4}, dag.Nodes)
+	assertar.Equal([]consensus.Weight{1, 2, 3, 4}, dag.Weights)
+	assertar.Equal([]consensus.ValidatorID{1, 2}, dag.Cheaters)
+
+	for seed := int64(0); seed < 10; seed++ {
+		r := rand.New(rand.NewSource(seed)) // nolint:gosec
+		data := make([]byte, 1+MaxFuzzValidators+3*MaxFuzzEvents+10)
+		r.Read(data)
+		dag := DecodeFuzzDAG(data)
+
+		var cheatersWeight, total consensus.Weight
+		for i, v := range dag.Nodes {
+			total += dag.Weights[i]
+			for _, cheater := range dag.Cheaters {
+				if cheater == v {
+					cheatersWeight += dag.Weights[i]
+				}
+			}
+		}
+		assertar.Less(3*cheatersWeight, total)
+
+		processed := map[consensus.EventHash]consensus.Event{}
+		events := dag.ForEach(ForEachEvent{
+			Process: func(e consensus.Event, name string) {
+				assertar.NotContains(processed, e.ID(), "%s is processed twice", name)
+				for _, p := range e.Parents() {
+					parent, ok := processed[p]
+					if assertar.True(ok, "parent of %s isn't processed", name) {
+						assertar.Less(parent.Lamport(), e.Lamport())
+					}
+				}
+				if sp := e.SelfParent(); sp != nil {
+					assertar.Equal(processed[*sp].Seq()+1, e.Seq())
+					assertar.Equal(e.Creator(), processed[*sp].Creator())
+				} else {
+					assertar.Equal(consensus.Seq(1), e.Seq())
+				}
+				processed[e.ID()] = e
+			},
+		})
+		count := 0
+		for _, ee := range events {
+			count += len(ee)
+		}
+		assertar.Equal(MaxFuzzEvents, count)
+
+		// the same input decodes into the same DAG
+		again := DecodeFuzzDAG(data).ForEach(ForEachEvent{})
+		assertar.Equal(events, again)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+var ErrModelInconsistentDecision = errors.New("roots decided a frame differently")
+
+// ModelBlock is a block decided by LachesisModel.
+type ModelBlock struct {
+	Frame    consensus.Frame
+	Atropos  consensus.EventHash
+	Cheaters consensus.Cheaters
+}
+
+// LachesisModel is a naive reference model of Lachesis, to test the engine against.
+// It computes the frames, roots and blocks of an epoch directly from the definitions, using the full
+// reachability of the DAG instead of vector clocks and recursive votes instead of the vote matrices.
+// It's quadratic in the number of events in memory, so it's only suitable for small DAGs.
+type LachesisModel struct {
+	validators *consensus.Validators
+
+	events map[consensus.EventHash]consensus.Event
+	order  consensus.EventHashes
+	// past is the set of the event and its ancestors
+	past map[consensus.EventHash]consensus.EventHashSet
+	// selfPast is the set of the event and its self-ancestors
+	selfPast map[consensus.EventHash]consensus.EventHashSet
+	frames   map[consensus.EventHash]consensus.Frame
+	roots    map[consensus.Frame]consensus.EventHashes
+
+	// caches of the calculations, which depend only on the past of the events
+	cheaters       map[consensus.EventHash]map[consensus.ValidatorID]bool
+	forklessCauses map[[2]consensus.EventHash]bool
+	votes          map[modelVoteKey]map[consensus.ValidatorID]bool
+}
+
+type modelVoteKey struct {
+	root  consensus.EventHash
+	frame consensus.Frame
+}
+
+// NewLachesisModel creates an empty model of an epoch of the validators.
+func NewLachesisModel(validators *consensus.Validators) *LachesisModel {
+	return &LachesisModel{
+		validators:     validators,
+		events:         map[consensus.EventHash]consensus.Event{},
+		past:           map[consensus.EventHash]consensus.EventHashSet{},
+		selfPast:       map[consensus.EventHash]consensus.EventHashSet{},
+		frames:         map[consensus.EventHash]consensus.Frame{},
+		roots:          map[consensus.Frame]consensus.EventHashes{},
+		cheaters:       map[consensus.EventHash]map[consensus.ValidatorID]bool{},
+		forklessCauses: map[[2]consensus.EventHash]bool{},
+		votes:          map[modelVoteKey]map[consensus.ValidatorID]bool{},
+	}
+}
+
+// Add adds the event, its parents must be added first. The frame of the event is calculated, not taken from it.
+func (m *LachesisModel) Add(e consensus.Event) error {
+	if _, ok := m.events[e.ID()]; ok {
+		return fmt.Errorf("event %s is added twice", e.ID().String())
+	}
+	if !m.validators.Exists(e.Creator()) {
+		return fmt.Errorf("creator %d of event %s isn't a validator", e.Creator(), e.ID().String())
+	}
+	past := consensus.NewEventsSet(e.ID())
+	for _, p := range e.Parents() {
+		if _, ok := m.events[p]; !ok {
+			return fmt.Errorf("parent %s of event %s not found", p.String(), e.ID().String())
+		}
+		for ancestor := range m.past[p] {
+			past.Add(ancestor)
+		}
+	}
+	selfPast := consensus.NewEventsSet(e.ID())
+	if sp := e.SelfParent(); sp != nil {
+		for ancestor := range m.selfPast[*sp] {
+			selfPast.Add(ancestor)
+		}
+	}
+	m.events[e.ID()] = e
+	m.order = append(m.order, e.ID())
+	m.past[e.ID()] = past
+	m.selfPast[e.ID()] = selfPast
+
+	// the event without a self-parent is a root of the first frame, otherwise it's a root of the next frame
+	// if it forkless causes the quorum of roots of the highest frame of the parents
+	frame, selfParentFrame := consensus.FirstFrame, consensus.Frame(0)
+	if sp := e.SelfParent(); sp != nil {
+		selfParentFrame = m.frames[*sp]
+		frame = selfParentFrame
+		for _, p := range e.Parents() {
+			frame = max(frame, m.frames[p])
+		}
+		counter := m.validators.NewCounter()
+		for _, root := range m.roots[frame] {
+			if m.ForklessCause(e.ID(), root) {
+				counter.Count(m.events[root].Creator())
+			}
+		}
+		if counter.HasQuorum() {
+			frame++
+		}
+	}
+	m.frames[e.ID()] = frame
+	if frame != selfParentFrame {
+		m.roots[frame] = append(m.roots[frame], e.ID())
+	}
+	return nil
+}
+
+// Frame returns the calculated frame of the event.
+func (m *LachesisModel) Frame(e consensus.EventHash) consensus.Frame {
+	return m.frames[e]
+}
+
+// Roots returns the roots of the frame, in the order of adding.
+func (m *LachesisModel) Roots(frame consensus.Frame) consensus.EventHashes {
+	return m.roots[frame]
+}
+
+// IsCheater returns true if a observes a fork of the validator,
+// i.e. two events of the validator, neither of which is a self-ancestor of the other.
+func (m *LachesisModel) IsCheater(a consensus.EventHash, validator consensus.ValidatorID) bool {
+	if cheaters, ok := m.cheaters[a]; ok {
+		return cheaters[validator]
+	}
+	// events of a non-cheater form a chain, so all of them are the self-ancestors of the highest one
+	highest := map[consensus.ValidatorID]consensus.Event{}
+	for id := range m.past[a] {
+		e := m.events[id]
+		if h, ok := highest[e.Creator()]; !ok || h.Seq() < e.Seq() {
+			highest[e.Creator()] = e
+		}
+	}
+	cheaters := map[consensus.ValidatorID]bool{}
+	for id := range m.past[a] {
+		e := m.events[id]
+		if !m.selfPast[highest[e.Creator()].ID()].Contains(id) {
+			cheaters[e.Creator()] = true
+		}
+	}
+	m.cheaters[a] = cheaters
+	return cheaters[validator]
+}
+
+// ForklessCause returns true if a doesn't observe b's creator as a cheater, and a observes
+// the events of the quorum of the validators, which observe b and aren't cheaters in a's view.
+func (m *LachesisModel) ForklessCause(a, b consensus.EventHash) bool {
+	key := [2]consensus.EventHash{a, b}
+	if res, ok := m.forklessCauses[key]; ok {
+		return res
+	}
+	res := false
+	if !m.IsCheater(a, m.events[b].Creator()) {
+		counter := m.validators.NewCounter()
+		for id := range m.past[a] {
+			e := m.events[id]
+			if m.past[id].Contains(b) && !m.IsCheater(a, e.Creator()) {
+				counter.Count(e.Creator())
+			}
+		}
+		res = counter.HasQuorum()
+	}
+	m.forklessCauses[key] = res
+	return res
+}
+
+// Blocks returns the decided blocks, in the order of the frames until the first undecided frame.
+// A frame is decided by the first added root which decides it, and all the other roots must decide it the same way.
+func (m *LachesisModel) Blocks() ([]ModelBlock, error) {
+	var blocks []ModelBlock
+	for frame := consensus.FirstFrame; len(m.roots[frame]) != 0; frame++ {
+		var decided *consensus.ValidatorID
+		for _, root := range m.order {
+			if m.frames[root] < frame+2 || !m.isRoot(root) {
+				continue
+			}
+			validator, ok := m.decide(root, frame)
+			if !ok {
+				continue
+			}
+			if decided == nil {
+				decided = &validator
+			} else if *decided != validator {
+				return blocks, fmt.Errorf("%w: frame %d is decided for validators %d and %d", ErrModelInconsistentDecision, frame, *decided, validator)
+			}
+		}
+		if decided == nil {
+			break
+		}
+		atropos := m.atropos(frame, *decided)
+		block := ModelBlock{Frame: frame, Atropos: atropos, Cheaters: consensus.Cheaters{}}
+		for _, validator := range m.validators.SortedIDs() {
+			if m.IsCheater(atropos, validator) {
+				block.Cheaters = append(block.Cheaters, validator)
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func (m *LachesisModel) isRoot(e consensus.EventHash) bool {
+	sp := m.events[e].SelfParent()
+	return sp == nil || m.frames[*sp] != m.frames[e]
+}
+
+// observedRoots returns the roots of the previous frame forkless caused by the root
+func (m *LachesisModel) observedRoots(root consensus.EventHash) consensus.EventHashes {
+	var observed consensus.EventHashes
+	for _, prev := range m.roots[m.frames[root]-1] {
+		if m.ForklessCause(root, prev) {
+			observed = append(observed, prev)
+		}
+	}
+	return observed
+}
+
+// vote returns the votes of the root for the candidates of the frame: a root of the next frame votes for the
+// validators whose roots it forkless causes, and a higher root votes as the weighted majority of the observed roots,
+// a tie is a yes vote
+func (m *LachesisModel) vote(root consensus.EventHash, frame consensus.Frame) map[consensus.ValidatorID]bool {
+	key := modelVoteKey{root, frame}
+	if votes, ok := m.votes[key]; ok {
+		return votes
+	}
+	votes := map[consensus.ValidatorID]bool{}
+	if m.frames[root] == frame+1 {
+		for _, observed := range m.observedRoots(root) {
+			votes[m.events[observed].Creator()] = true
+		}
+	} else {
+		sums := m.aggregate(root, frame)
+		for _, validator := range m.validators.IDs() {
+			votes[validator] = sums[validator] >= 0
+		}
+	}
+	m.votes[key] = votes
+	return votes
+}
+
+// aggregate sums the weighted votes of the roots observed by the root, returns the sums and the observed weight
+func (m *LachesisModel) aggregate(root consensus.EventHash, frame consensus.Frame) map[consensus.ValidatorID]int64 {
+	sums := map[consensus.ValidatorID]int64{}
+	for _, observed := range m.observedRoots(root) {
+		weight := int64(m.validators.Get(m.events[observed].Creator()))
+		for _, validator := range m.validators.IDs() {
+			if m.vote(observed, frame)[validator] {
+				sums[validator] += weight
+			} else {
+				sums[validator] -= weight
+			}
+		}
+	}
+	return sums
+}
+
+// decide returns the validator of the Atropos of the frame, if the root decides it.
+// The candidates are checked in the order of the sorted validators, until the first one which isn't rejected.
+func (m *LachesisModel) decide(root consensus.EventHash, frame consensus.Frame) (consensus.ValidatorID, bool) {
+	observedWeight := int64(0)
+	for _, observed := range m.observedRoots(root) {
+		observedWeight += int64(m.validators.Get(m.events[observed].Creator()))
+	}
+	// sum = yes - no and observedWeight = yes + no, the candidate is decided once the yes votes reach the threshold,
+	// and it's rejected once the no votes reach it
+	sums := m.aggregate(root, frame)
+	for _, validator := range m.validators.SortedIDs() {
+		yes := (observedWeight + sums[validator]) / 2
+		no := (observedWeight - sums[validator]) / 2
+		if m.reachesThreshold(yes) {
+			return validator, true
+		}
+		if !m.reachesThreshold(no) {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// reachesThreshold returns true if the weight is at least the threshold of the total weight,
+// i.e. 3*weight >= 2*total for the default 2/3 threshold. It's derived independently of the engine's quorum helpers.
+func (m *LachesisModel) reachesThreshold(weight int64) bool {
+	numerator, denominator := uint64(2), uint64(3)
+	if threshold := m.validators.QuorumThreshold(); threshold != (consensus.QuorumThreshold{}) {
+		numerator, denominator = threshold.Numerator, threshold.Denominator
+	}
+	lhs := new(big.Int).Mul(big.NewInt(weight), new(big.Int).SetUint64(denominator))
+	rhs := new(big.Int).Mul(new(big.Int).SetUint64(uint64(m.validators.TotalWeight())), new(big.Int).SetUint64(numerator))
+	return lhs.Cmp(rhs) >= 0
+}
+
+// atropos returns the root of the validator in the frame. Among the forks, it's the one forkless caused by
+// a root of the next frame, which is unique.
+func (m *LachesisModel) atropos(frame consensus.Frame, validator consensus.ValidatorID) consensus.EventHash {
+	var candidates consensus.EventHashes
+	for _, root := range m.roots[frame] {
+		if m.events[root].Creator() == validator {
+			candidates = append(candidates, root)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return bytes.Compare(candidates[i].Bytes(), candidates[j].Bytes()) < 0
+	})
+	if len(candidates) > 1 {
+		for _, candidate := range candidates {
+			for _, judge := range m.roots[frame+1] {
+				if m.ForklessCause(judge, candidate) {
+					return candidate
+				}
+			}
+		}
+	}
+	return candidates[len(candidates)-1]
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"slices"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// MinimizeDAG shrinks the DAG by delta debugging, while the shrunk DAG still fails.
+// The events must be ordered so that parents precede their children, e.g. by Lamport timestamp.
+// The DAG is shrunk by cutting the tails of the validators' events, and the whole validators' events,
+// along with all their descendants, so that every shrunk DAG is closed under the parent relation.
+// In the result, cutting the last event of any validator, or any whole validator, no longer fails.
+// The events are returned in the original order. fails is expected to hold for the initial DAG.
+func MinimizeDAG(events consensus.Events, fails func(consensus.Events) bool) consensus.Events {
+	for changed := true; changed; {
+		changed = false
+		// cut halves of the tails first, then quarters and so on, as the early cuts remove most of the descendants
+		for _, creator := range creators(events) {
+			seqs := creatorSeqs(events, creator)
+			for n := (len(seqs) + 1) / 2; n > 0 && len(seqs) > 0; {
+				n = min(n, len(seqs))
+				if shrunk := cutTail(events, creator, seqs[len(seqs)-n]); fails(shrunk) {
+					events = shrunk
+					seqs = creatorSeqs(events, creator)
+					changed = true
+					continue
+				}
+				n /= 2
+			}
+		}
+		for _, creator := range creators(events) {
+			if shrunk := cutTail(events, creator, 0); fails(shrunk) {
+				events = shrunk
+				changed = true
+			}
+		}
+	}
+	return events
+}
+
+// cutTail removes the events of the creator starting from the seq, along with their descendants
+func cutTail(events consensus.Events, creator consensus.ValidatorID, from consensus.Seq) consensus.Events {
+	removed := consensus.EventHashSet{}
+	res := make(consensus.Events, 0, len(events))
+	for _, e := range events {
+		cut := e.Creator() == creator && e.Seq() >= from
+		for _, p := range e.Parents() {
+			cut = cut || removed.Contains(p)
+		}
+		if cut {
+			removed.Add(e.ID())
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// creators returns the sorted creators of the events
+func creators(events consensus.Events) []consensus.ValidatorID {
+	set := map[consensus.ValidatorID]struct{}{}
+	for _, e := range events {
+		set[e.Creator()] = struct{}{}
+	}
+	res := make([]consensus.ValidatorID, 0, len(set))
+	for creator := range set {
+		res = append(res, creator)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// creatorSeqs returns the sorted distinct seqs of the creator's events, forks share a seq
+func creatorSeqs(events consensus.Events, creator consensus.ValidatorID) []consensus.Seq {
+	var res []consensus.Seq
+	for _, e := range events {
+		if e.Creator() == creator {
+			res = append(res, e.Seq())
+		}
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
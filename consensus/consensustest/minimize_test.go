@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+func TestMinimizeDAG(t *testing.T) {
+	t.Run("events", func(t *testing.T) {
+		testMinimizeDAG(t, 0)
+	})
+	t.Run("forks", func(t *testing.T) {
+		testMinimizeDAG(t, 3)
+	})
+}
+
+func testMinimizeDAG(t *testing.T, forksCount int) {
+	assertar := assert.New(t)
+
+	nodes := GenNodes(5)
+	var events consensus.Events
+	r := rand.New(rand.NewSource(1)) // nolint:gosec
+	ForEachRandFork(nodes, nodes[:1], 30, 3, forksCount, r, ForEachEvent{
+		Process: func(e consensus.Event, name string) {
+			events = append(events, e)
+		},
+	})
+	byID := map[consensus.EventHash]consensus.Event{}
+	for _, e := range events {
+		byID[e.ID()] = e
+	}
+
+	// the failure is reproduced by the past cone of the target event
+	target := events[len(events)*2/3]
+	cone := consensus.EventHashSet{}
+	var walk func(id consensus.EventHash)
+	walk = func(id consensus.EventHash) {
+		if cone.Contains(id) {
+			return
+		}
+		cone.Add(id)
+		for _, p := range byID[id].Parents() {
+			walk(p)
+		}
+	}
+	walk(target.ID())
+
+	calls := 0
+	minimized := MinimizeDAG(events, func(shrunk consensus.Events) bool {
+		calls++
+		// every shrunk DAG is closed under the parent relation
+		present := consensus.EventHashSet{}
+		for _, e := range shrunk {
+			for _, p := range e.Parents() {
+				assertar.True(present.Contains(p))
+			}
+			present.Add(e.ID())
+		}
+		return present.Contains(target.ID())
+	})
+	assertar.Less(calls, len(events))
+
+	position := map[consensus.EventHash]int{}
+	for i, e := range events {
+		position[e.ID()] = i
+	}
+	for i := 1; i < len(minimized); i++ {
+		assertar.Less(position[minimized[i-1].ID()], position[minimized[i].ID()])
+	}
+	if forksCount == 0 {
+		// the tails are cut exactly down to the past cone
+		assertar.Equal(cone, minimized.IDs().Set())
+	} else {
+		// a fork branch may remain if its seq is below the cone's one
+		for id := range cone {
+			assertar.True(minimized.IDs().Set().Contains(id))
+		}
+		assertar.Less(len(minimized), len(events))
+	}
+
+	scheme, err := DAGtoASCIIscheme(minimized)
+	assertar.NoError(err)
+	assertar.NotEmpty(scheme)
+
+	// a failure which doesn't depend on the DAG
+	assertar.Empty(MinimizeDAG(events, func(consensus.Events) bool { return true }))
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// SimNode is a consensus instance driven by Network, e.g. a Lachesis with its own event source.
+type SimNode interface {
+	// Build fills the consensus fields of a new event of the node, the event is dropped if it returns an error
+	Build(e consensus.MutableEvent) error
+	// Process takes the event into processing, the parents of the event are processed first
+	Process(e consensus.Event) error
+}
+
+// NetworkConfig is the configuration of Network. The time is measured in abstract ticks.
+type NetworkConfig struct {
+	// Duration is the time during which the nodes emit events
+	Duration int
+	// EmitInterval is the time between the events of a node, the first event is emitted at a random time within it
+	EmitInterval int
+	// MinLatency and MaxLatency bound the random latency of an event sent to a node,
+	// the spread of the latencies reorders the events, so that children may arrive before their parents
+	MinLatency, MaxLatency int
+	// Parents is the max number of parents of an event
+	Parents int
+	// Partitions cut the delivery between the groups of nodes in the time [From, To).
+	// The events sent across a partition are resent once it heals.
+	Partitions []Partition
+}
+
+// ErrInvalidNetworkConfig is returned by Run if the NetworkConfig can't be simulated.
+var ErrInvalidNetworkConfig = errors.New("invalid network config")
+
+// Validate checks that the config can be simulated, it's called by Run.
+func (c NetworkConfig) Validate() error {
+	switch {
+	case c.Duration <= 0:
+		return fmt.Errorf("%w: duration %d", ErrInvalidNetworkConfig, c.Duration)
+	case c.EmitInterval <= 0:
+		return fmt.Errorf("%w: emit interval %d", ErrInvalidNetworkConfig, c.EmitInterval)
+	case c.MinLatency < 0 || c.MaxLatency < c.MinLatency:
+		return fmt.Errorf("%w: latency [%d, %d]", ErrInvalidNetworkConfig, c.MinLatency, c.MaxLatency)
+	case c.Parents <= 0:
+		return fmt.Errorf("%w: %d parents", ErrInvalidNetworkConfig, c.Parents)
+	}
+	return nil
+}
+
+// Network is a deterministic discrete-event simulator of the nodes connected by a network.
+// Every node emits events on its own view of the DAG, and buffers the arrived events until their parents
+// are processed, so every node processes the events in its own order.
+type Network struct {
+	config NetworkConfig
+	r      *rand.Rand
+
+	ids   []consensus.ValidatorID
+	nodes map[consensus.ValidatorID]SimNode
+	views map[consensus.ValidatorID]*nodeView
+
+	now       int
+	queue     simQueue
+	scheduled int
+	events    consensus.Events
+	err       error
+}
+
+// simAction is a scheduled emission of an event by the node, or a delivery of the event to the node
+type simAction struct {
+	at  int
+	seq int
+	to  consensus.ValidatorID
+	// e is nil for the emission
+	e *TestEvent
+}
+
+// simQueue is the heap of the actions ordered by time, and by the order of scheduling within the same time
+type simQueue []*simAction
+
+func (q simQueue) Len() int { return len(q) }
+
+func (q simQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q simQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *simQueue) Push(x any) { *q = append(*q, x.(*simAction)) }
+
+func (q *simQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// NewNetwork creates a network without nodes.
+func NewNetwork(config NetworkConfig, r *rand.Rand) *Network {
+	if r == nil {
+		// fixed seed
+		r = rand.New(rand.NewSource(0)) // nolint:gosec
+	}
+	return &Network{
+		config: config,
+		r:      r,
+		nodes:  map[consensus.ValidatorID]SimNode{},
+		views:  map[consensus.ValidatorID]*nodeView{},
+	}
+}
+
+// AddNode connects the node of the validator to the network.
+func (n *Network) AddNode(id consensus.ValidatorID, node SimNode) {
+	n.ids = append(n.ids, id)
+	n.nodes[id] = node
+	n.views[id] = &nodeView{
+		id:     id,
+		known:  consensus.EventHashSet{},
+		latest: map[consensus.ValidatorID]consensus.Event{},
+	}
+}
+
+// Run emits the events until the Duration, and then delivers all the events to all the nodes.
+// Returns the first error of a node, or an error if the config is invalid.
+func (n *Network) Run() error {
+	if err := n.config.Validate(); err != nil {
+		return err
+	}
+	for _, id := range n.ids {
+		n.schedule(n.r.Intn(n.config.EmitInterval), id, nil)
+	}
+	for n.queue.Len() != 0 && n.err == nil {
+		action := heap.Pop(&n.queue).(*simAction)
+		n.now = action.at
+		if action.e == nil {
+			n.emit(action.to)
+			continue
+		}
+		n.views[action.to].receive(action.e, n.process)
+	}
+	return n.err
+}
+
+// Now returns the current time of the simulation.
+func (n *Network) Now() int {
+	return n.now
+}
+
+// Events returns the emitted events, in the order of emission.
+func (n *Network) Events() consensus.Events {
+	return n.events
+}
+
+func (n *Network) schedule(at int, to consensus.ValidatorID, e *TestEvent) {
+	n.scheduled++
+	heap.Push(&n.queue, &simAction{at: at, seq: n.scheduled, to: to, e: e})
+}
+
+func (n *Network) emit(id consensus.ValidatorID) {
+	view := n.views[id]
+	e := view.next(n.config.Parents, n.r)
+	e.Name = fmt.Sprintf("%d_%03d", id, len(view.own))
+	if err := n.nodes[id].Build(e); err == nil {
+		e.SetID(CalcHashForTestEvent(e))
+		consensus.SetEventName(e.ID(), e.Name)
+		view.own = append(view.own, e)
+		n.events = append(n.events, e)
+		view.receive(e, n.process)
+		for _, to := range n.ids {
+			if to != id {
+				n.schedule(n.sendTime(id, to)+n.latency(), to, e)
+			}
+		}
+	}
+	if next := n.now + n.config.EmitInterval; next < n.config.Duration {
+		n.schedule(next, id, nil)
+	}
+}
+
+// sendTime returns the time when the event is sent from the node to the other one, i.e. now or after the partitions heal
+func (n *Network) sendTime(from, to consensus.ValidatorID) int {
+	at := n.now
+	for separated := true; separated; {
+		separated = false
+		for i := range n.config.Partitions {
+			if p := &n.config.Partitions[i]; p.separates(at, from, to) {
+				at = p.To
+				separated = true
+			}
+		}
+	}
+	return at
+}
+
+func (n *Network) latency() int {
+	return n.config.MinLatency + n.r.Intn(n.config.MaxLatency-n.config.MinLatency+1)
+}
+
+func (n *Network) process(view *nodeView, e consensus.Event) {
+	if n.err != nil {
+		return
+	}
+	if err := n.nodes[view.id].Process(e); err != nil {
+		n.err = fmt.Errorf("node %d failed to process event %s at %d: %w", view.id, e.ID().String(), n.now, err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensustest
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// testSimNode records the processed events along with the time
+type testSimNode struct {
+	id        consensus.ValidatorID
+	network   *Network
+	processed map[consensus.EventHash]int
+	order     consensus.EventHashes
+	fail      error
+}
+
+func (n *testSimNode) Build(e consensus.MutableEvent) error {
+	e.SetEpoch(FakeEpoch())
+	return nil
+}
+
+func (n *testSimNode) Process(e consensus.Event) error {
+	for _, p := range e.Parents() {
+		if _, ok := n.processed[p]; !ok {
+			return errors.New("parent isn't processed")
+		}
+	}
+	n.processed[e.ID()] = n.network.Now()
+	n.order = append(n.order, e.ID())
+	return n.fail
+}
+
+func TestNetwork(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := GenNodes(4)
+	partition := Partition{From: 300, To: 600, Group: nodes[:1]}
+	network := NewNetwork(NetworkConfig{
+		Duration:     1000,
+		EmitInterval: 10,
+		MinLatency:   0,
+		MaxLatency:   50,
+		Parents:      3,
+		Partitions:   []Partition{partition},
+	}, rand.New(rand.NewSource(0))) // nolint:gosec
+	simNodes := make([]*testSimNode, len(nodes))
+	for i, v := range nodes {
+		simNodes[i] = &testSimNode{id: v, network: network, processed: map[consensus.EventHash]int{}}
+		network.AddNode(v, simNodes[i])
+	}
+	if !assertar.NoError(network.Run()) {
+		return
+	}
+
+	events := network.Events()
+	assertar.Len(events, 4*100)
+	reordered := false
+	for i, node := range simNodes {
+		assertar.Len(node.processed, len(events), "node %d", i)
+		reordered = reordered || !assert.ObjectsAreEqual(simNodes[0].order, node.order)
+	}
+	assertar.True(reordered)
+
+	// the events emitted during the partition don't cross it until it heals
+	byID := map[consensus.ValidatorID]*testSimNode{}
+	for _, node := range simNodes {
+		byID[node.id] = node
+	}
+	for _, e := range events {
+		// the creator processes its event on the emission
+		emitted := byID[e.Creator()].processed[e.ID()]
+		for _, node := range simNodes {
+			if partition.separates(emitted, e.Creator(), node.id) {
+				assertar.GreaterOrEqual(node.processed[e.ID()], partition.To)
+			}
+		}
+	}
+
+	// the errors of the nodes are returned
+	failing := NewNetwork(NetworkConfig{Duration: 100, EmitInterval: 10, Parents: 2}, nil)
+	for _, v := range nodes {
+		failing.AddNode(v, &testSimNode{id: v, network: failing, processed: map[consensus.EventHash]int{}, fail: errors.New("failed")})
+	}
+	assertar.Error(failing.Run())
+}
+
+func TestNetworkConfig_Validate(t *testing.T) {
+	assertar := assert.New(t)
+
+	valid := NetworkConfig{Duration: 100, EmitInterval: 10, MinLatency: 1, MaxLatency: 1, Parents: 2}
+	assertar.NoError(valid.Validate())
+	for _, mutate := range []func(c *NetworkConfig){
+		func(c *NetworkConfig) { c.Duration = 0 },
+		func(c *NetworkConfig) { c.EmitInterval = 0 },
+		func(c *NetworkConfig) { c.EmitInterval = -1 },
+		func(c *NetworkConfig) { c.MinLatency = -1 },
+		func(c *NetworkConfig) { c.MaxLatency = 0 },
+		func(c *NetworkConfig) { c.Parents = 0 },
+	} {
+		config := valid
+		mutate(&config)
+		assertar.ErrorIs(config.Validate(), ErrInvalidNetworkConfig, "%+v", config)
+	}
+
+	// the zero config fails instead of running forever
+	network := NewNetwork(NetworkConfig{Duration: 100}, nil)
+	network.AddNode(1, &testSimNode{id: 1, network: network, processed: map[consensus.EventHash]int{}})
+	assertar.ErrorIs(network.Run(), ErrInvalidNetworkConfig)
+	assertar.Empty(network.Events())
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIntegrity is the root of all the errors after which consensus state can no longer be trusted.
+	// The node should stop processing events and report the error.
+	ErrIntegrity = errors.New("consensus integrity failure")
+
+	// ErrStorageIO is returned when the underlying key-value database fails to read or write.
+	ErrStorageIO = fmt.Errorf("%w: storage I/O", ErrIntegrity)
+	// ErrMissingEvent is returned when an event (or its index) referenced by the DAG is not found.
+	ErrMissingEvent = fmt.Errorf("%w: missing event", ErrIntegrity)
+	// ErrCorruptedRecord is returned when a stored record cannot be decoded.
+	ErrCorruptedRecord = fmt.Errorf("%w: corrupted record", ErrIntegrity)
+	// ErrInconsistentDB is returned when stored records contradict each other.
+	ErrInconsistentDB = fmt.Errorf("%w: inconsistent DB", ErrIntegrity)
+
+	// ErrUnknownParent is returned when a parent of a new event isn't processed yet.
+	// Unlike ErrIntegrity, it's an invalid input, and the consensus state remains consistent.
+	ErrUnknownParent = errors.New("unknown parent")
+)
+
+// IntegrityErrorf formats an error of the specified kind.
+// errors.Is matches the result against the kind, ErrIntegrity and any %w-wrapped error.
+func IntegrityErrorf(kind error, format string, args ...any) error {
+	return fmt.Errorf("%w: %w", kind, fmt.Errorf(format, args...))
+}
@@ -83,6 +83,16 @@ func (h EventHash) Hex() string {
 	return Hash(h).Hex()
 }
 
+// MarshalText returns the hex representation of h.
+func (h EventHash) MarshalText() ([]byte, error) {
+	return Hash(h).MarshalText()
+}
+
+// UnmarshalText parses an event hash in hex syntax.
+func (h *EventHash) UnmarshalText(input []byte) error {
+	return (*Hash)(h).UnmarshalText(input)
+}
+
 // Lamport returns [4:8] bytes, which store event's Lamport.
 func (h EventHash) Lamport() Lamport {
 	return BytesToLamport(h[4:8])
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// QuorumThreshold is the fault-tolerance threshold of validators:
+// a quorum is a weight strictly greater than Numerator/Denominator of the total weight.
+//
+// Fault model: the consensus stays safe and live as long as the weight of the Byzantine validators
+// doesn't exceed MaxFaultyWeight, i.e. the total weight minus the quorum.
+// The zero value stands for DefaultQuorumThreshold.
+type QuorumThreshold struct {
+	Numerator   uint64
+	Denominator uint64
+}
+
+// DefaultQuorumThreshold is the classic BFT threshold, which tolerates less than 1/3W of Byzantine validators.
+var DefaultQuorumThreshold = QuorumThreshold{Numerator: 2, Denominator: 3}
+
+var (
+	ErrUnsafeQuorumThreshold = errors.New("unsafe quorum threshold")
+)
+
+// Validate checks that the threshold is safe.
+// Thresholds below 2/3 allow two quorums to intersect only by Byzantine validators,
+// thresholds of 1 and above make a quorum unreachable.
+func (t QuorumThreshold) Validate() error {
+	t = t.orDefault()
+	if t.Denominator == 0 {
+		return fmt.Errorf("%w: zero denominator", ErrUnsafeQuorumThreshold)
+	}
+	if t.Numerator >= t.Denominator {
+		return fmt.Errorf("%w: %d/%d isn't less than 1", ErrUnsafeQuorumThreshold, t.Numerator, t.Denominator)
+	}
+	// compare in 128 bits, i.e. 3*Numerator < 2*Denominator
+	hi1, lo1 := bits.Mul64(t.Numerator, 3)
+	hi2, lo2 := bits.Mul64(t.Denominator, 2)
+	if hi1 < hi2 || hi1 == hi2 && lo1 < lo2 {
+		return fmt.Errorf("%w: %d/%d is less than 2/3", ErrUnsafeQuorumThreshold, t.Numerator, t.Denominator)
+	}
+	return nil
+}
+
+// Quorum returns the minimal weight which is greater than the threshold of total weight.
+func (t QuorumThreshold) Quorum(total Weight) Weight {
+	t = t.orDefault()
+	return t.mulDiv(total, false) + 1
+}
+
+// ElectionQuorum returns ceil(2*threshold*total), where threshold is Numerator/Denominator.
+// Election decides once the difference of the yes and no votes reaches ElectionQuorum - observedWeight,
+// i.e. once the yes votes reach the threshold.
+func (t QuorumThreshold) ElectionQuorum(total Weight) Weight {
+	t = t.orDefault()
+	return t.mulDiv(2*total, true)
+}
+
+// MaxFaultyWeight returns the maximum weight of Byzantine validators tolerated under the threshold.
+func (t QuorumThreshold) MaxFaultyWeight(total Weight) Weight {
+	return total - t.Quorum(total)
+}
+
+func (t QuorumThreshold) orDefault() QuorumThreshold {
+	if t == (QuorumThreshold{}) {
+		return DefaultQuorumThreshold
+	}
+	return t
+}
+
+// mulDiv returns w*Numerator/Denominator without an overflow, as Numerator < Denominator
+func (t QuorumThreshold) mulDiv(w Weight, roundUp bool) Weight {
+	hi, lo := bits.Mul64(uint64(w), t.Numerator)
+	quo, rem := bits.Div64(hi, lo, t.Denominator)
+	if roundUp && rem != 0 {
+		quo++
+	}
+	return Weight(quo)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package consensus
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorumThreshold_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		threshold QuorumThreshold
+		valid     bool
+	}{
+		{QuorumThreshold{}, true},
+		{QuorumThreshold{2, 3}, true},
+		{QuorumThreshold{4, 6}, true},
+		{QuorumThreshold{3, 4}, true},
+		{QuorumThreshold{math.MaxUint64 - 1, math.MaxUint64}, true},
+		{QuorumThreshold{math.MaxUint64 / 3 * 2, math.MaxUint64 / 3 * 3}, true},
+		{QuorumThreshold{1, 2}, false},
+		{QuorumThreshold{66, 100}, false},
+		{QuorumThreshold{1, 1}, false},
+		{QuorumThreshold{4, 3}, false},
+		{QuorumThreshold{1, 0}, false},
+		{QuorumThreshold{0, 1}, false},
+	} {
+		err := tt.threshold.Validate()
+		if tt.valid {
+			assert.NoError(t, err, tt.threshold)
+		} else {
+			assert.ErrorIs(t, err, ErrUnsafeQuorumThreshold, tt.threshold)
+		}
+	}
+}
+
+func TestQuorumThreshold_Default(t *testing.T) {
+	for _, total := range []Weight{1, 2, 3, 4, 10, 100, 101, math.MaxUint32, MaxTotalWeight - 1, MaxTotalWeight} {
+		for _, threshold := range []QuorumThreshold{{}, DefaultQuorumThreshold} {
+			// the classic formulas
+			assert.Equal(t, total*2/3+1, threshold.Quorum(total))
+			assert.Equal(t, (4*total+3-1)/3, threshold.ElectionQuorum(total))
+			assert.Equal(t, total-(total*2/3+1), threshold.MaxFaultyWeight(total))
+		}
+	}
+}
+
+func TestQuorumThreshold_Custom(t *testing.T) {
+	threshold := QuorumThreshold{3, 4}
+	assert.Equal(t, Weight(76), threshold.Quorum(100))
+	assert.Equal(t, Weight(24), threshold.MaxFaultyWeight(100))
+	assert.Equal(t, Weight(150), threshold.ElectionQuorum(100))
+	assert.Equal(t, Weight(152), threshold.ElectionQuorum(101))
+	assert.Equal(t, Weight(3<<59), threshold.Quorum(MaxTotalWeight))
+
+	v := ArrayToValidators([]ValidatorID{1, 2, 3, 4}, []Weight{25, 25, 25, 25})
+	assert.Equal(t, Weight(67), v.Quorum())
+	custom := v.WithQuorumThreshold(threshold)
+	assert.Equal(t, Weight(67), v.Quorum())
+	assert.Equal(t, Weight(76), custom.Quorum())
+	assert.Equal(t, Weight(24), custom.MaxFaultyWeight())
+	assert.Equal(t, threshold, custom.Copy().QuorumThreshold())
+	assert.Equal(t, v.Hash(), custom.Hash())
+
+	counter := custom.NewCounter()
+	counter.Count(1)
+	counter.Count(2)
+	counter.Count(3)
+	assert.False(t, counter.HasQuorum())
+	counter.Count(4)
+	assert.True(t, counter.HasQuorum())
+
+	assert.Panics(t, func() { v.WithQuorumThreshold(QuorumThreshold{1, 2}) })
+}
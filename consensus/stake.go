@@ -10,11 +10,18 @@
 
 package consensus
 
+import "math"
+
 type (
 	// Weight amount.
-	Weight uint32
+	// Its RLP encoding is independent of the integer width, so the records with 32-bit weights are decoded as is.
+	Weight uint64
 )
 
+// MaxTotalWeight is the limit of the validators total weight.
+// The election math multiplies the total weight by 4 in the signed 64-bit arithmetic.
+const MaxTotalWeight = Weight(math.MaxInt64 / 4)
+
 type (
 	// WeightCounterProvider providers weight counter.
 	WeightCounterProvider func() *WeightCounter
@@ -14,6 +14,9 @@ import (
 	"math/big"
 )
 
+// maxTotalWeightBits is the bit length of MaxTotalWeight
+const maxTotalWeightBits = 61
+
 // ValidatorsBuilderBig is a helper to create Validators object out of bigint numbers
 type ValidatorsBigBuilder map[ValidatorID]*big.Int
 
@@ -44,9 +47,10 @@ func (vv ValidatorsBigBuilder) TotalWeight() *big.Int {
 func (vv ValidatorsBigBuilder) Build() *Validators {
 	totalBits := vv.TotalWeight().BitLen()
 	// use downscaling by a 2^n ratio, instead of n for simplicity and performance reasons
+	// total weight is kept below 2^maxTotalWeightBits, i.e. not greater than MaxTotalWeight
 	shift := uint(0)
-	if totalBits > 31 {
-		shift = uint(totalBits - 31)
+	if totalBits > maxTotalWeightBits {
+		shift = uint(totalBits - maxTotalWeightBits)
 	}
 
 	builder := NewBuilder()
@@ -11,9 +11,9 @@
 package consensus
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
-	"math"
 	"sort"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -25,13 +25,16 @@ type (
 		weights     []Weight
 		ids         []ValidatorID
 		totalWeight Weight
+		quorum      Weight
 	}
 	// Validators group of an epoch with weights.
 	// Optimized for BFT algorithm calculations.
 	// Read-only.
 	Validators struct {
 		values map[ValidatorID]Weight
-		cache  cache
+		// threshold isn't a part of the RLP encoding
+		threshold QuorumThreshold
+		cache     cache
 	}
 
 	// ValidatorsBuilder is a helper to create Validators object
@@ -54,7 +57,7 @@ func (vv ValidatorsBuilder) Set(id ValidatorID, weight Weight) {
 
 // Build new read-only Validators object
 func (vv ValidatorsBuilder) Build() *Validators {
-	return newValidators(vv)
+	return newValidators(vv, QuorumThreshold{})
 }
 
 // EqualWeightValidators builds new read-only Validators object with equal weights (for tests)
@@ -76,14 +79,15 @@ func ArrayToValidators(ids []ValidatorID, weights []Weight) *Validators {
 }
 
 // newValidators builds new read-only Validators object
-func newValidators(values ValidatorsBuilder) *Validators {
+func newValidators(values ValidatorsBuilder, threshold QuorumThreshold) *Validators {
 	valuesCopy := make(ValidatorsBuilder)
 	for id, s := range values {
 		valuesCopy.Set(id, s)
 	}
 
 	vv := &Validators{
-		values: valuesCopy,
+		values:    valuesCopy,
+		threshold: threshold,
 	}
 	vv.cache = vv.calcCaches()
 	return vv
@@ -113,9 +117,10 @@ func (vv *Validators) calcCaches() cache {
 			panic("validators weight overflow")
 		}
 	}
-	if cache.totalWeight > math.MaxUint32/2 {
+	if cache.totalWeight > MaxTotalWeight {
 		panic("validators weight overflow")
 	}
+	cache.quorum = vv.threshold.Quorum(cache.totalWeight)
 
 	return cache
 }
@@ -183,7 +188,21 @@ func (vv *Validators) sortedArray() validators {
 
 // Copy constructs a copy.
 func (vv *Validators) Copy() *Validators {
-	return newValidators(vv.values)
+	return newValidators(vv.values, vv.threshold)
+}
+
+// WithQuorumThreshold constructs a copy with the specified fault-tolerance threshold.
+// It panics if threshold isn't valid, see QuorumThreshold.Validate.
+func (vv *Validators) WithQuorumThreshold(threshold QuorumThreshold) *Validators {
+	if err := threshold.Validate(); err != nil {
+		panic(err)
+	}
+	return newValidators(vv.values, threshold)
+}
+
+// QuorumThreshold returns the fault-tolerance threshold of validators, the zero value stands for the default one.
+func (vv *Validators) QuorumThreshold() QuorumThreshold {
+	return vv.threshold
 }
 
 // Builder returns a mutable copy of content
@@ -193,7 +212,12 @@ func (vv *Validators) Builder() ValidatorsBuilder {
 
 // Quorum limit of validators.
 func (vv *Validators) Quorum() Weight {
-	return vv.TotalWeight()*2/3 + 1
+	return vv.cache.quorum
+}
+
+// MaxFaultyWeight returns the maximum weight of Byzantine validators tolerated by the quorum.
+func (vv *Validators) MaxFaultyWeight() Weight {
+	return vv.TotalWeight() - vv.Quorum()
 }
 
 // TotalWeight of validators.
@@ -206,6 +230,15 @@ func (vv *Validators) EncodeRLP(w io.Writer) error {
 	return rlp.Encode(w, vv.sortedArray())
 }
 
+// Hash returns SHA-256 of the RLP encoding of validators, it doesn't depend on the order of insertion.
+func (vv *Validators) Hash() Hash {
+	b, err := rlp.EncodeToBytes(vv)
+	if err != nil {
+		panic(err) // encoding of a slice of integers cannot fail
+	}
+	return sha256.Sum256(b)
+}
+
 // DecodeRLP is for RLP deserialization.
 func (vv *Validators) DecodeRLP(s *rlp.Stream) error {
 	var arr []validator
@@ -17,6 +17,7 @@ import (
 	"unsafe"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -139,8 +140,21 @@ func maxBig(n uint) *big.Int {
 	return max
 }
 
+func TestValidators_Hash(t *testing.T) {
+	b1 := NewBuilder()
+	b1.Set(1, 10)
+	b1.Set(2, 20)
+	b2 := NewBuilder()
+	b2.Set(2, 20)
+	b2.Set(1, 10)
+	assert.Equal(t, b1.Build().Hash(), b2.Build().Hash())
+
+	b2.Set(2, 21)
+	assert.NotEqual(t, b1.Build().Hash(), b2.Build().Hash())
+}
+
 func TestValidators_Big(t *testing.T) {
-	max := Weight(math.MaxUint32 >> 1)
+	max := MaxTotalWeight
 
 	b := NewBigBuilder()
 
@@ -149,7 +163,7 @@ func TestValidators_Big(t *testing.T) {
 	assert.Equal(t, Weight(1), v.TotalWeight())
 	assert.Equal(t, Weight(1), v.Get(1))
 
-	b.Set(2, big.NewInt(int64(max)-1))
+	b.Set(2, new(big.Int).SetUint64(uint64(max)-1))
 	v = b.Build()
 	assert.Equal(t, max, v.TotalWeight())
 	assert.Equal(t, Weight(1), v.Get(1))
@@ -170,37 +184,68 @@ func TestValidators_Big(t *testing.T) {
 	assert.Equal(t, Weight(0), v.Get(3))
 	assert.Equal(t, Weight(1), v.Get(4))
 
-	b.Set(5, maxBig(60))
+	b.Set(5, maxBig(90))
 	v = b.Build()
-	assert.Equal(t, Weight(0x40000000), v.TotalWeight())
+	assert.Equal(t, Weight(1<<60+1<<31-2), v.TotalWeight())
 	assert.Equal(t, Weight(0), v.Get(1))
-	assert.Equal(t, Weight(0x1), v.Get(2))
+	assert.Equal(t, Weight(1<<31-1), v.Get(2))
 	assert.Equal(t, Weight(0), v.Get(3))
 	assert.Equal(t, Weight(0), v.Get(4))
 	assert.Equal(t, max/2, v.Get(5))
 
-	b.Set(1, maxBig(501))
-	b.Set(2, maxBig(502))
-	b.Set(3, maxBig(503))
-	b.Set(4, maxBig(504))
-	b.Set(5, maxBig(515))
+	b.Set(1, maxBig(531))
+	b.Set(2, maxBig(532))
+	b.Set(3, maxBig(533))
+	b.Set(4, maxBig(534))
+	b.Set(5, maxBig(545))
 	v = b.Build()
-	assert.Equal(t, Weight(0x400efffb), v.TotalWeight())
-	assert.Equal(t, Weight(0xffff), v.Get(1))
-	assert.Equal(t, Weight(0x1ffff), v.Get(2))
-	assert.Equal(t, Weight(0x3ffff), v.Get(3))
-	assert.Equal(t, Weight(0x7ffff), v.Get(4))
-	assert.Equal(t, Weight(0x3fffffff), v.Get(5))
+	assert.Equal(t, Weight(1<<46+1<<47+1<<48+1<<49+1<<60-5), v.TotalWeight())
+	assert.Equal(t, Weight(1<<46-1), v.Get(1))
+	assert.Equal(t, Weight(1<<47-1), v.Get(2))
+	assert.Equal(t, Weight(1<<48-1), v.Get(3))
+	assert.Equal(t, Weight(1<<49-1), v.Get(4))
+	assert.Equal(t, Weight(1<<60-1), v.Get(5))
 
 	for v := ValidatorID(1); v <= 5000; v++ {
-		b.Set(v, new(big.Int).Mul(big.NewInt(int64(v)), maxBig(400)))
+		b.Set(v, new(big.Int).Mul(big.NewInt(int64(v)), maxBig(430)))
 	}
 	v = b.Build()
-	assert.Equal(t, Weight(0x5f62de78), v.TotalWeight())
-	assert.Equal(t, Weight(0x7f), v.Get(1))
-	assert.Equal(t, Weight(0xff), v.Get(2))
-	assert.Equal(t, Weight(0x17f), v.Get(3))
-	assert.Equal(t, Weight(0x4e1ff), v.Get(2500))
-	assert.Equal(t, Weight(0x9c37f), v.Get(4999))
-	assert.Equal(t, Weight(0x9c3ff), v.Get(5000))
+	assert.Equal(t, Weight(12502500<<37-5000), v.TotalWeight())
+	assert.Equal(t, Weight(1<<37-1), v.Get(1))
+	assert.Equal(t, Weight(2<<37-1), v.Get(2))
+	assert.Equal(t, Weight(3<<37-1), v.Get(3))
+	assert.Equal(t, Weight(2500<<37-1), v.Get(2500))
+	assert.Equal(t, Weight(4999<<37-1), v.Get(4999))
+	assert.Equal(t, Weight(5000<<37-1), v.Get(5000))
+	assert.LessOrEqual(t, v.TotalWeight(), MaxTotalWeight)
+}
+
+func TestValidators_Overflow(t *testing.T) {
+	b := NewBuilder()
+	b.Set(1, MaxTotalWeight)
+	assert.Equal(t, MaxTotalWeight, b.Build().TotalWeight())
+	assert.Equal(t, MaxTotalWeight*2/3+1, b.Build().Quorum())
+
+	b.Set(2, 1)
+	assert.Panics(t, func() { b.Build() })
+}
+
+// TestValidators_LegacyRLP checks that validators encoded with 32-bit weights are decoded as is
+func TestValidators_LegacyRLP(t *testing.T) {
+	type legacyValidator struct {
+		ID     ValidatorID
+		Weight uint32
+	}
+	legacy := []legacyValidator{{1, math.MaxUint32 / 4}, {2, 7}}
+	b, err := rlp.EncodeToBytes(legacy)
+	assert.NoError(t, err)
+
+	v := &Validators{}
+	assert.NoError(t, rlp.DecodeBytes(b, v))
+	assert.Equal(t, Weight(math.MaxUint32/4), v.Get(1))
+	assert.Equal(t, Weight(7), v.Get(2))
+
+	reencoded, err := rlp.EncodeToBytes(v)
+	assert.NoError(t, err)
+	assert.Equal(t, b, reencoded)
 }
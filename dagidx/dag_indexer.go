@@ -39,7 +39,25 @@ type ForklessCause interface {
 	// unless more than 1/3W are Byzantine.
 	// This great property is the reason why this function exists,
 	// providing the base for the BFT algorithm.
-	ForklessCause(aID, bID consensus.EventHash) bool
+	//
+	// An error is returned if any of the events isn't indexed or the index is unreadable.
+	ForklessCause(aID, bID consensus.EventHash) (bool, error)
+}
+
+type ForklessCauseProgress interface {
+	// ForklessCauseProgress calculates the progress of the forkless causing of B by a new event,
+	// whose self-parent is A and whose other parents are the chosenParents.
+	// The first result is the weight of the validators counted towards the quorum by such event,
+	// the second one is the same weight for every candidate parent, if it's added to the chosenParents.
+	ForklessCauseProgress(aID, bID consensus.EventHash, candidateParents, chosenParents consensus.EventHashes) (*consensus.WeightCounter, []*consensus.WeightCounter, error)
+}
+
+type ParentSelector interface {
+	// ChooseParents chooses the parents of a new event on top of the self-parent, among the heads.
+	// The result starts with the self-parent, followed by at most maxParents-1 heads ranked by how much they
+	// advance the forkless causing of the roots by the new event. The heads of the cheaters observed by the
+	// self-parent are excluded, and at most one head of a validator is chosen.
+	ChooseParents(selfParent consensus.EventHash, heads, roots consensus.EventHashes, maxParents int) (consensus.EventHashes, error)
 }
 
 type VectorClock interface {
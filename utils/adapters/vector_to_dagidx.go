@@ -16,6 +16,11 @@ import (
 	"github.com/0xsoniclabs/consensus/vecengine"
 )
 
+var (
+	_ dagidx.ForklessCauseProgress = (*VectorToDagIndexer)(nil)
+	_ dagidx.ParentSelector        = (*VectorToDagIndexer)(nil)
+)
+
 type VectorSeqToDagIndexSeq struct {
 	*vecengine.HighestBeforeSeq
 }
@@ -45,5 +50,10 @@ type VectorToDagIndexer struct {
 }
 
 func (v *VectorToDagIndexer) GetMergedHighestBefore(id consensus.EventHash) dagidx.HighestBeforeSeq {
+	if v.Engine.GetHighestBefore(id) == nil {
+		// the event isn't indexed, and the interface has no error to report it,
+		// so return the empty vector, which observes no events, instead of merging a missing vector
+		return VectorSeqToDagIndexSeq{vecengine.NewHighestBeforeSeq(0)}
+	}
 	return VectorSeqToDagIndexSeq{v.Engine.GetMergedHighestBefore(id).(*vecengine.HighestBeforeSeq)}
 }
@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryRegistry keeps the metrics in memory.
+// The values can be read directly, or exported in the Prometheus text format with WriteTo.
+type MemoryRegistry struct {
+	mu         sync.Mutex
+	counters   map[string]*MemoryCounter
+	gauges     map[string]*MemoryGauge
+	histograms map[string]*MemoryHistogram
+}
+
+var _ Registry = (*MemoryRegistry)(nil)
+
+// NewMemoryRegistry creates an empty in-memory registry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		counters:   make(map[string]*MemoryCounter),
+		gauges:     make(map[string]*MemoryGauge),
+		histograms: make(map[string]*MemoryHistogram),
+	}
+}
+
+// Counter returns the counter of the name, creating it if needed.
+func (r *MemoryRegistry) Counter(name string) Counter {
+	return r.GetCounter(name)
+}
+
+// Gauge returns the gauge of the name, creating it if needed.
+func (r *MemoryRegistry) Gauge(name string) Gauge {
+	return r.GetGauge(name)
+}
+
+// Histogram returns the histogram of the name, creating it if needed.
+func (r *MemoryRegistry) Histogram(name string) Histogram {
+	return r.GetHistogram(name)
+}
+
+// GetCounter returns the counter of the name, creating it if needed.
+func (r *MemoryRegistry) GetCounter(name string) *MemoryCounter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.counters[name]; !ok {
+		r.counters[name] = &MemoryCounter{}
+	}
+	return r.counters[name]
+}
+
+// GetGauge returns the gauge of the name, creating it if needed.
+func (r *MemoryRegistry) GetGauge(name string) *MemoryGauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.gauges[name]; !ok {
+		r.gauges[name] = &MemoryGauge{}
+	}
+	return r.gauges[name]
+}
+
+// GetHistogram returns the histogram of the name, creating it if needed.
+func (r *MemoryRegistry) GetHistogram(name string) *MemoryHistogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.histograms[name]; !ok {
+		r.histograms[name] = &MemoryHistogram{}
+	}
+	return r.histograms[name]
+}
+
+// WriteTo writes all the metrics in the Prometheus text exposition format, sorted by name.
+// Histograms are exported as summaries without quantiles.
+func (r *MemoryRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var lines []string
+	for name, c := range r.counters {
+		lines = append(lines, fmt.Sprintf("# TYPE %s counter\n%s %d\n", name, name, c.Value()))
+	}
+	for name, g := range r.gauges {
+		lines = append(lines, fmt.Sprintf("# TYPE %s gauge\n%s %d\n", name, name, g.Value()))
+	}
+	for name, h := range r.histograms {
+		s := h.Snapshot()
+		lines = append(lines, fmt.Sprintf("# TYPE %s summary\n%s_sum %g\n%s_count %d\n", name, name, s.Sum, name, s.Count))
+	}
+	r.mu.Unlock()
+
+	sort.Strings(lines)
+	var total int64
+	for _, line := range lines {
+		n, err := io.WriteString(w, line)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// MemoryCounter is an in-memory Counter.
+type MemoryCounter struct {
+	v atomic.Int64
+}
+
+// Inc increases the counter by delta.
+func (c *MemoryCounter) Inc(delta int64) {
+	c.v.Add(delta)
+}
+
+// Value returns the current value.
+func (c *MemoryCounter) Value() int64 {
+	return c.v.Load()
+}
+
+// MemoryGauge is an in-memory Gauge.
+type MemoryGauge struct {
+	v atomic.Int64
+}
+
+// Update sets the gauge value.
+func (g *MemoryGauge) Update(value int64) {
+	g.v.Store(value)
+}
+
+// Value returns the current value.
+func (g *MemoryGauge) Value() int64 {
+	return g.v.Load()
+}
+
+// MemoryHistogram is an in-memory Histogram, which keeps the aggregates of the observations.
+type MemoryHistogram struct {
+	mu sync.Mutex
+	s  HistogramSnapshot
+}
+
+// HistogramSnapshot is a copy of the histogram aggregates.
+type HistogramSnapshot struct {
+	Count uint64
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// Mean returns the average of the observations, or 0 if there are none.
+func (s HistogramSnapshot) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Observe adds the observation.
+func (h *MemoryHistogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.s.Count == 0 {
+		h.s.Min, h.s.Max = value, value
+	}
+	h.s.Count++
+	h.s.Sum += value
+	h.s.Min = math.Min(h.s.Min, value)
+	h.s.Max = math.Max(h.s.Max, value)
+}
+
+// Snapshot returns the current aggregates.
+func (h *MemoryHistogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.s
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRegistry(t *testing.T) {
+	assertar := assert.New(t)
+
+	r := NewMemoryRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Counter("events").Inc(2)
+			r.Histogram("latency").Observe(float64(i))
+		}()
+	}
+	wg.Wait()
+	r.Gauge("size").Update(5)
+	r.Gauge("size").Update(3)
+
+	assertar.Equal(int64(20), r.GetCounter("events").Value())
+	assertar.Equal(int64(3), r.GetGauge("size").Value())
+	assertar.Equal(HistogramSnapshot{Count: 10, Sum: 45, Min: 0, Max: 9}, r.GetHistogram("latency").Snapshot())
+	assertar.Equal(4.5, r.GetHistogram("latency").Snapshot().Mean())
+	assertar.Equal(0.0, r.GetHistogram("empty").Snapshot().Mean())
+
+	var out strings.Builder
+	n, err := r.WriteTo(&out)
+	assertar.NoError(err)
+	assertar.Equal(int64(out.Len()), n)
+	assertar.Equal(`# TYPE empty summary
+empty_sum 0
+empty_count 0
+# TYPE events counter
+events 20
+# TYPE latency summary
+latency_sum 45
+latency_count 10
+# TYPE size gauge
+size 3
+`, out.String())
+}
+
+func TestOrNop(t *testing.T) {
+	assert.Equal(t, Nop, OrNop(nil))
+	r := NewMemoryRegistry()
+	assert.Equal(t, Registry(r), OrNop(r))
+	// Nop metrics don't panic
+	Nop.Counter("a").Inc(1)
+	Nop.Gauge("a").Update(1)
+	Nop.Histogram("a").Observe(1)
+}
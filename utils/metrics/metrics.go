@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package metrics
+
+type (
+	// Registry creates and owns the named metrics.
+	// Requesting the same name twice returns the same metric.
+	// Implementations must be safe for concurrent use.
+	Registry interface {
+		Counter(name string) Counter
+		Gauge(name string) Gauge
+		Histogram(name string) Histogram
+	}
+
+	// Counter is a monotonically increasing value.
+	Counter interface {
+		Inc(delta int64)
+	}
+
+	// Gauge is a value which can go up and down.
+	Gauge interface {
+		Update(value int64)
+	}
+
+	// Histogram samples observations, e.g. latencies or sizes.
+	Histogram interface {
+		Observe(value float64)
+	}
+)
+
+// Nop is a registry of metrics which discard all the updates.
+var Nop Registry = nopRegistry{}
+
+// OrNop returns the registry, or Nop if it's nil.
+func OrNop(r Registry) Registry {
+	if r == nil {
+		return Nop
+	}
+	return r
+}
+
+type (
+	nopRegistry  struct{}
+	nopCounter   struct{}
+	nopGauge     struct{}
+	nopHistogram struct{}
+)
+
+func (nopRegistry) Counter(string) Counter     { return nopCounter{} }
+func (nopRegistry) Gauge(string) Gauge         { return nopGauge{} }
+func (nopRegistry) Histogram(string) Histogram { return nopHistogram{} }
+func (nopCounter) Inc(int64)                   {}
+func (nopGauge) Update(int64)                  {}
+func (nopHistogram) Observe(float64)           {}
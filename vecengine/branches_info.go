@@ -23,6 +23,8 @@ type BranchesInfo struct {
 
 // InitBranchesInfo loads BranchesInfo from store
 func (vi *Engine) InitBranchesInfo() {
+	vi.mu.Lock()
+	defer vi.mu.Unlock()
 	if vi.bi == nil {
 		// if not cached
 		vi.bi = vi.getBranchesInfo()
@@ -11,8 +11,6 @@
 package vecengine
 
 import (
-	"fmt"
-
 	"github.com/0xsoniclabs/consensus/consensus"
 )
 
@@ -34,39 +32,56 @@ type kv struct {
 // unless more than 1/3W are Byzantine.
 // This great property is the reason why this function exists,
 // providing the base for the BFT algorithm.
-func (vi *Engine) ForklessCause(aID, bID consensus.EventHash) bool {
-	if res, ok := vi.cache.ForklessCause.Get(kv{aID, bID}); ok {
-		return res.(bool)
+func (vi *Engine) ForklessCause(aID, bID consensus.EventHash) (bool, error) {
+	if err := vi.Err(); err != nil {
+		return false, err
+	}
+	vi.mu.Lock()
+	cached, ok := vi.cache.ForklessCause.Get(kv{aID, bID})
+	vi.mu.Unlock()
+	if ok {
+		vi.metrics.forklessCauseCacheHits.Inc(1)
+		return cached.(bool), nil
 	}
+	vi.metrics.forklessCauseCacheMisses.Inc(1)
 
 	vi.InitBranchesInfo()
-	res := vi.forklessCause(aID, bID)
+	res, err := vi.forklessCause(aID, bID)
+	if err == nil {
+		err = vi.Err()
+	}
+	if err != nil {
+		return false, err
+	}
 
+	vi.mu.Lock()
 	vi.cache.ForklessCause.Add(kv{aID, bID}, res, 1)
-	return res
+	vi.mu.Unlock()
+	return res, nil
 }
 
-func (vi *Engine) forklessCause(aID, bID consensus.EventHash) bool {
+func (vi *Engine) forklessCause(aID, bID consensus.EventHash) (bool, error) {
 	// Get events by hash
 	a := vi.GetHighestBefore(aID)
 	if a == nil {
-		vi.crit(fmt.Errorf("Event A=%s not found", aID.String()))
-		return false
+		return false, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Event A=%s not found", aID.String())
 	}
 
 	// check A doesn't observe any forks from B
 	if vi.AtLeastOneFork() {
-		bBranchID := vi.GetEventBranchID(bID)
+		bBranchID, err := vi.GetEventBranchID(bID)
+		if err != nil {
+			return false, err
+		}
 		if a.Get(bBranchID).IsForkDetected() { // B is observed as cheater by A
-			return false
+			return false, nil
 		}
 	}
 
 	// check A observes that {QUORUM} non-cheater-validators observe B
 	b := vi.GetLowestAfter(bID)
 	if b == nil {
-		vi.crit(fmt.Errorf("Event B=%s not found", bID.String()))
-		return false
+		return false, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Event B=%s not found", bID.String())
 	}
 
 	yes := vi.validators.NewCounter()
@@ -87,10 +102,10 @@ func (vi *Engine) forklessCause(aID, bID consensus.EventHash) bool {
 			yes.CountByIdx(creatorIdx)
 		}
 	}
-	return yes.HasQuorum()
+	return yes.HasQuorum(), nil
 }
 
-func (vi *Engine) ForklessCauseProgress(aID, bID consensus.EventHash, candidateParents, chosenParents consensus.EventHashes) (*consensus.WeightCounter, []*consensus.WeightCounter) {
+func (vi *Engine) ForklessCauseProgress(aID, bID consensus.EventHash, candidateParents, chosenParents consensus.EventHashes) (*consensus.WeightCounter, []*consensus.WeightCounter, error) {
 	// This function is used to determine progress of event bID in forkless causing aID.
 	// It may be used to determine progress toward the forkless cause condition for an event not in vi, but whose parents are in vi.
 	// To do so, aID should be the self-parent while chosenParents should be the parents of the not-yet-created event.
@@ -112,16 +127,14 @@ func (vi *Engine) ForklessCauseProgress(aID, bID consensus.EventHash, candidateP
 	// Get events by hash
 	aHB := vi.GetHighestBefore(aID)
 	if aHB == nil {
-		vi.crit(fmt.Errorf("Event A=%s not found", aID.String()))
-		return chosenParentsFCProgress, candidateParentsFCProgress
+		return nil, nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Event A=%s not found", aID.String())
 	}
 
 	candidateParentsHB := make([]*HighestBeforeSeq, len(candidateParents))
 	for i, _ := range candidateParents {
 		candidateParentsHB[i] = vi.GetHighestBefore(candidateParents[i])
 		if candidateParentsHB[i] == nil {
-			vi.crit(fmt.Errorf("Candidate parent=%s not found", candidateParents[i].String()))
-			return chosenParentsFCProgress, candidateParentsFCProgress
+			return nil, nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Candidate parent=%s not found", candidateParents[i].String())
 		}
 	}
 
@@ -129,43 +142,38 @@ func (vi *Engine) ForklessCauseProgress(aID, bID consensus.EventHash, candidateP
 	for i, _ := range chosenParents {
 		chosenParentsHB[i] = vi.GetHighestBefore(chosenParents[i])
 		if chosenParentsHB[i] == nil {
-			vi.crit(fmt.Errorf("Chosen parent=%s not found", chosenParents[i].String()))
-			return chosenParentsFCProgress, candidateParentsFCProgress
+			return nil, nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Chosen parent=%s not found", chosenParents[i].String())
 		}
 	}
 
-	// check A doesn't observe any forks from B
 	if vi.AtLeastOneFork() {
-		bBranchID := vi.GetEventBranchID(bID)
+		bBranchID, err := vi.GetEventBranchID(bID)
+		if err != nil {
+			return nil, nil, err
+		}
+		// check A doesn't observe any forks from B
 		if aHB.Get(bBranchID).IsForkDetected() { // B is observed as cheater by A
-			return chosenParentsFCProgress, candidateParentsFCProgress
+			return chosenParentsFCProgress, candidateParentsFCProgress, nil
 		}
-	}
 
-	// check chosenParents don't observe any forks from B
-	for i := 0; i < len(chosenParentsHB); i++ {
-		if vi.AtLeastOneFork() {
-			bBranchID := vi.GetEventBranchID(bID)
+		// check chosenParents don't observe any forks from B
+		for i := 0; i < len(chosenParentsHB); i++ {
 			if chosenParentsHB[i].Get(bBranchID).IsForkDetected() { // B is observed as cheater by a chosen parent
-				return chosenParentsFCProgress, candidateParentsFCProgress
+				return chosenParentsFCProgress, candidateParentsFCProgress, nil
 			}
 		}
-	}
 
-	// check candidateParents don't observe any forks from B
-	for i := 0; i < len(candidateParentsHB); i++ {
-		if vi.AtLeastOneFork() {
-			bBranchID := vi.GetEventBranchID(bID)
+		// check candidateParents don't observe any forks from B
+		for i := 0; i < len(candidateParentsHB); i++ {
 			if candidateParentsHB[i].Get(bBranchID).IsForkDetected() { // B is observed as cheater by a candidate parent
-				return chosenParentsFCProgress, candidateParentsFCProgress
+				return chosenParentsFCProgress, candidateParentsFCProgress, nil
 			}
 		}
 	}
 
 	bLA := vi.GetLowestAfter(bID)
 	if bLA == nil {
-		vi.crit(fmt.Errorf("Event B=%s not found", bID.String()))
-		return chosenParentsFCProgress, candidateParentsFCProgress
+		return nil, nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Event B=%s not found", bID.String())
 	}
 
 	// calculate forkless causing using the indexes
@@ -208,13 +216,16 @@ func (vi *Engine) ForklessCauseProgress(aID, bID consensus.EventHash, candidateP
 	// aID may not contribute to forkless cause without the heads,
 	// but may contribute with the heads. HighestBefore and LowestAfter used above do not incorporate
 	// these potential new events, so ensure the contribution of aID's creator is checked and made here
-	aCreatorID := vi.getEvent(aID).Creator()
+	a := vi.getEvent(aID)
+	if a == nil {
+		return nil, nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Event A=%s not found", aID.String())
+	}
 	for _, FC := range candidateParentsFCProgress {
 		if FC.Sum() > 0 { // if anything in candidate event's subgraph observes bID, then the candidate must too
-			FC.Count(aCreatorID)
+			FC.Count(a.Creator())
 		}
 	}
-	return chosenParentsFCProgress, candidateParentsFCProgress
+	return chosenParentsFCProgress, candidateParentsFCProgress, vi.Err()
 }
 
 func maxEvent(a consensus.Seq, b consensus.Seq) consensus.Seq {
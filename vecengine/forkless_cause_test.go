@@ -19,6 +19,7 @@ import (
 
 	"github.com/0xsoniclabs/consensus/consensus"
 	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+	"github.com/0xsoniclabs/consensus/utils/metrics"
 	"github.com/0xsoniclabs/consensus/vecflushable"
 
 	"github.com/stretchr/testify/assert"
@@ -148,6 +149,91 @@ a1_5 ────╣        ║
 
 }
 
+func TestForklessCause_MissingEvent(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes, _, named := consensustest.ASCIIschemeToDAG(`
+a1_1  b1_1
+║     ║
+a2_2 ─╣
+║     ║
+`)
+	validators := consensus.EqualWeightValidators(nodes, 1)
+	events := make(map[consensus.EventHash]consensus.Event)
+	getEvent := func(id consensus.EventHash) consensus.Event {
+		return events[id]
+	}
+
+	vi := NewIndex(nil, LiteConfig(), GetEngineCallbacks)
+	vi.Reset(validators, flushable.Wrap(memorydb.New()), getEvent)
+	for _, name := range []string{"a1_1", "b1_1"} {
+		events[named[name].ID()] = named[name]
+		assertar.NoError(vi.Add(named[name]))
+		assertar.NoError(vi.Flush())
+	}
+
+	// a2_2 is not indexed
+	_, err := vi.ForklessCause(named["a2_2"].ID(), named["a1_1"].ID())
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+	assertar.ErrorIs(err, consensus.ErrIntegrity)
+	_, err = vi.ForklessCause(named["a1_1"].ID(), named["a2_2"].ID())
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+
+	// parent b1_1 is not known to the event source
+	delete(events, named["b1_1"].ID())
+	err = vi.Add(named["a2_2"])
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+
+	// parent b1_1 isn't indexed, it's an invalid input rather than an integrity failure
+	events[named["b1_1"].ID()] = named["b1_1"]
+	vi.Reset(validators, flushable.Wrap(memorydb.New()), getEvent)
+	assertar.NoError(vi.Add(named["a1_1"]))
+	err = vi.Add(named["a2_2"])
+	assertar.ErrorIs(err, consensus.ErrUnknownParent)
+	assertar.NotErrorIs(err, consensus.ErrIntegrity)
+
+	// self-parent a1_1 isn't indexed
+	vi.Reset(validators, flushable.Wrap(memorydb.New()), getEvent)
+	assertar.NoError(vi.Add(named["b1_1"]))
+	err = vi.Add(named["a2_2"])
+	assertar.ErrorIs(err, consensus.ErrUnknownParent)
+	assertar.NotErrorIs(err, consensus.ErrIntegrity)
+}
+
+func TestForklessCause_CacheMetrics(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes, _, named := consensustest.ASCIIschemeToDAG(`
+a1_1  b1_1
+║     ║
+a2_2 ─╣
+║     ║
+`)
+	validators := consensus.EqualWeightValidators(nodes, 1)
+	events := make(map[consensus.EventHash]consensus.Event)
+	getEvent := func(id consensus.EventHash) consensus.Event {
+		return events[id]
+	}
+
+	registry := metrics.NewMemoryRegistry()
+	cfg := LiteConfig()
+	cfg.Metrics = registry
+	vi := NewIndex(nil, cfg, GetEngineCallbacks)
+	vi.Reset(validators, flushable.Wrap(memorydb.New()), getEvent)
+	for _, name := range []string{"a1_1", "b1_1", "a2_2"} {
+		events[named[name].ID()] = named[name]
+		assertar.NoError(vi.Add(named[name]))
+		assertar.NoError(vi.Flush())
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := vi.ForklessCause(named["a2_2"].ID(), named["b1_1"].ID())
+		assertar.NoError(err)
+	}
+	assertar.Equal(int64(2), registry.GetCounter(MetricForklessCauseCacheHits).Value())
+	assertar.Equal(int64(1), registry.GetCounter(MetricForklessCauseCacheMisses).Value())
+}
+
 // testForklessCaused uses event name agreement:
 //
 //	"<name>_<level>[(by-level)]",
@@ -187,9 +273,11 @@ func testForklessCaused(t *testing.T, dagAscii string) {
 
 			who := by.ID()
 			whom := ev.ID()
+			res, err := vi.ForklessCause(who, whom)
+			assertar.NoError(err)
 			if !assertar.Equal(
 				bylevel > 0 && bylevel <= level,
-				vi.ForklessCause(who, whom),
+				res,
 				fmt.Sprintf("%s forkless sees %s", who.String(), whom.String()),
 			) {
 				return
@@ -525,9 +613,11 @@ func testForklessCausedRandom(t *testing.T, dbProducer func() kvdb.FlushableKVSt
 	for e1name, e1 := range named {
 		for e2name, e2 := range named {
 			_, expect := relations[e1name][e2name]
+			res, err := vi.ForklessCause(e1.ID(), e2.ID())
+			assertar.NoError(err)
 			if !assertar.Equal(
 				expect,
-				vi.ForklessCause(e1.ID(), e2.ID()),
+				res,
 				fmt.Sprintf("%s forkless sees %s", e1.ID(), e2.ID()),
 			) {
 				return
@@ -760,7 +850,9 @@ func TestRandomForks(t *testing.T) {
 						a: a.ID(),
 						b: b.ID(),
 					}
-					forklessCauseMap[pair] = vi.ForklessCause(a.ID(), b.ID())
+					res, err := vi.ForklessCause(a.ID(), b.ID())
+					assertar.NoError(err)
+					forklessCauseMap[pair] = res
 				}
 			}
 
@@ -790,7 +882,8 @@ func TestRandomForks(t *testing.T) {
 							a: a.ID(),
 							b: b.ID(),
 						}
-						res := vi.ForklessCause(a.ID(), b.ID())
+						res, err := vi.ForklessCause(a.ID(), b.ID())
+						assertar.NoError(err)
 						assertar.Equal(forklessCauseMap[pair], res, "%s %s %d", a.ID().String(), b.ID().String(), reorderTry)
 					}
 				}
@@ -11,12 +11,13 @@
 package vecengine
 
 import (
-	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/0xsoniclabs/cacheutils/cachescale"
 	"github.com/0xsoniclabs/cacheutils/simplewlru"
 	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/utils/metrics"
 
 	"github.com/0xsoniclabs/kvdb"
 	"github.com/0xsoniclabs/kvdb/table"
@@ -33,8 +34,14 @@ type Callbacks struct {
 }
 
 // Index is a data to detect forkless-cause condition, calculate median timestamp, detect forks.
+// The read-only methods (ForklessCause, GetMergedHighestBefore and the vector getters) are safe
+// for concurrent use with each other, but not with Add, Flush, DropNotFlushed and Reset.
 type Engine struct {
-	crit          func(error)
+	crit func(error)
+	// mu guards the caches, lazy loading of the branches info and the sticky error,
+	// which are mutated on the read-only paths
+	mu            sync.Mutex
+	err           error
 	validators    *consensus.Validators
 	validatorIdxs map[consensus.ValidatorID]consensus.ValidatorIndex
 
@@ -60,9 +67,15 @@ type Engine struct {
 	}
 
 	cfg IndexConfig
+
+	metrics struct {
+		forklessCauseCacheHits   metrics.Counter
+		forklessCauseCacheMisses metrics.Counter
+	}
 }
 
 // NewIndex creates Index instance.
+// crit is optional, it's called on every integrity error in addition to returning it.
 func NewIndex(crit func(error), config IndexConfig, getCallbacks func(vi *Engine) Callbacks) *Engine {
 	vi := &Engine{
 		cfg:  config,
@@ -70,25 +83,56 @@ func NewIndex(crit func(error), config IndexConfig, getCallbacks func(vi *Engine
 	}
 	vi.Callbacks = getCallbacks(vi)
 	vi.initCaches()
+	vi.initMetrics()
 
 	return vi
 }
 
 // Add calculates vector clocks for the event and saves into DB.
 func (vi *Engine) Add(e consensus.Event) error {
+	if vi.err != nil {
+		return vi.err
+	}
 	vi.InitBranchesInfo()
 	_, err := vi.fillEventVectors(e)
-	return err
+	if err != nil {
+		return err
+	}
+	return vi.err
 }
 
 // Flush writes vector clocks to persistent store.
-func (vi *Engine) Flush() {
+func (vi *Engine) Flush() error {
 	if vi.bi != nil {
 		vi.setBranchesInfo(vi.bi)
 	}
+	if vi.err != nil {
+		return vi.err
+	}
 	if err := vi.vecDb.Flush(); err != nil {
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err))
+	}
+	return vi.err
+}
+
+// Err returns the first integrity error hit by the index since the last Reset.
+// Once an error is hit, it's returned by every subsequent Add, Flush and ForklessCause call.
+func (vi *Engine) Err() error {
+	vi.mu.Lock()
+	defer vi.mu.Unlock()
+	return vi.err
+}
+
+// fail remembers an error hit on a path which isn't able to return it, e.g. the vector getters.
+func (vi *Engine) fail(err error) {
+	if vi.crit != nil {
 		vi.crit(err)
 	}
+	vi.mu.Lock()
+	defer vi.mu.Unlock()
+	if vi.err == nil {
+		vi.err = err
+	}
 }
 
 // DropNotFlushed not connected clocks. Call it if event has failed.
@@ -112,10 +156,10 @@ func (vi *Engine) setForkDetected(before HighestBeforeI, branchID consensus.Vali
 func (vi *Engine) fillGlobalBranchID(e consensus.Event, meIdx consensus.ValidatorIndex) (consensus.ValidatorIndex, error) {
 	// sanity checks
 	if len(vi.bi.BranchIDCreatorIdxs) != len(vi.bi.BranchIDLastSeq) {
-		return 0, errors.New("inconsistent BranchIDCreators len (inconsistent DB)")
+		return 0, consensus.IntegrityErrorf(consensus.ErrInconsistentDB, "inconsistent BranchIDCreators len")
 	}
 	if consensus.ValidatorIndex(len(vi.bi.BranchIDCreatorIdxs)) < vi.validators.Len() {
-		return 0, errors.New("inconsistent BranchIDCreators len (inconsistent DB)")
+		return 0, consensus.IntegrityErrorf(consensus.ErrInconsistentDB, "inconsistent BranchIDCreators len")
 	}
 
 	if e.SelfParent() == nil {
@@ -126,10 +170,13 @@ func (vi *Engine) fillGlobalBranchID(e consensus.Event, meIdx consensus.Validato
 			return meIdx, nil
 		}
 	} else {
-		selfParentBranchID := vi.GetEventBranchID(*e.SelfParent())
+		selfParentBranchID, err := vi.GetEventBranchID(*e.SelfParent())
+		if err != nil {
+			return 0, err
+		}
 		// sanity checks
 		if len(vi.bi.BranchIDCreatorIdxs) != len(vi.bi.BranchIDLastSeq) {
-			return 0, errors.New("inconsistent BranchIDCreators len (inconsistent DB)")
+			return 0, consensus.IntegrityErrorf(consensus.ErrInconsistentDB, "inconsistent BranchIDCreators len")
 		}
 
 		if vi.bi.BranchIDLastSeq[selfParentBranchID]+1 == e.Seq() {
@@ -155,19 +202,26 @@ func (vi *Engine) fillEventVectors(e consensus.Event) (allVecs, error) {
 		after:  vi.Callbacks.NewLowestAfter(consensus.ValidatorIndex(len(vi.bi.BranchIDCreatorIdxs))),
 	}
 
+	// pre-load parents into RAM for quick access, a parent which isn't indexed is an invalid input,
+	// so it's checked before the branches are updated
+	parentsVecs := make([]HighestBeforeI, len(e.Parents()))
+	for i, p := range e.Parents() {
+		parentsVecs[i] = vi.Callbacks.GetHighestBefore(p)
+		if parentsVecs[i] == nil {
+			return myVecs, fmt.Errorf("%w: processed out of order, parent=%s", consensus.ErrUnknownParent, p.String())
+		}
+	}
+
 	meBranchID, err := vi.fillGlobalBranchID(e, meIdx)
 	if err != nil {
 		return myVecs, err
 	}
 
-	// pre-load parents into RAM for quick access
-	parentsVecs := make([]HighestBeforeI, len(e.Parents()))
 	parentsBranchIDs := make([]consensus.ValidatorIndex, len(e.Parents()))
 	for i, p := range e.Parents() {
-		parentsBranchIDs[i] = vi.GetEventBranchID(p)
-		parentsVecs[i] = vi.Callbacks.GetHighestBefore(p)
-		if parentsVecs[i] == nil {
-			return myVecs, fmt.Errorf("processed out of order, parent not found (inconsistent DB), parent=%s", p.String())
+		parentsBranchIDs[i], err = vi.GetEventBranchID(p)
+		if err != nil {
+			return myVecs, err
 		}
 	}
 
@@ -232,7 +286,7 @@ func (vi *Engine) fillEventVectors(e consensus.Event) (allVecs, error) {
 	}
 	err = vi.DfsSubgraph(e, onWalk)
 	if err != nil {
-		vi.crit(err)
+		return myVecs, err
 	}
 
 	// store calculated vectors
@@ -266,13 +320,32 @@ func (vi *Engine) initCaches() {
 	vi.cache.LowestAfterSeq, _ = simplewlru.New(vi.cfg.Caches.LowestAfterSeqSize, int(vi.cfg.Caches.HighestBeforeSeqSize))
 }
 
+// Names of the index metrics.
+const (
+	MetricForklessCauseCacheHits   = "vecengine_forkless_cause_cache_hits"
+	MetricForklessCauseCacheMisses = "vecengine_forkless_cause_cache_misses"
+)
+
+func (vi *Engine) initMetrics() {
+	registry := metrics.OrNop(vi.cfg.Metrics)
+	vi.metrics.forklessCauseCacheHits = registry.Counter(MetricForklessCauseCacheHits)
+	vi.metrics.forklessCauseCacheMisses = registry.Counter(MetricForklessCauseCacheMisses)
+}
+
 func GetEngineCallbacks(vi *Engine) Callbacks {
 	return Callbacks{
+		// nil vectors must be returned as untyped nil, so that callers are able to detect missing events
 		GetHighestBefore: func(event consensus.EventHash) HighestBeforeI {
-			return vi.GetHighestBefore(event)
+			if b := vi.GetHighestBefore(event); b != nil {
+				return b
+			}
+			return nil
 		},
 		GetLowestAfter: func(event consensus.EventHash) LowestAfterI {
-			return vi.GetLowestAfter(event)
+			if b := vi.GetLowestAfter(event); b != nil {
+				return b
+			}
+			return nil
 		},
 		SetHighestBefore: func(event consensus.EventHash, b HighestBeforeI) {
 			vi.SetHighestBefore(event, b.(*HighestBeforeSeq))
@@ -296,6 +369,7 @@ func (vi *Engine) Reset(validators *consensus.Validators, db kvdb.FlushableKVSto
 	vi.vecDb = db
 	vi.validators = validators
 	vi.validatorIdxs = validators.Idxs()
+	vi.err = nil
 	vi.DropNotFlushed()
 	table.MigrateTables(&vi.table, vi.vecDb)
 	vi.getEvent = getEvent
@@ -313,6 +387,8 @@ type IndexCacheConfig struct {
 // IndexConfig - Engine config (cache sizes)
 type IndexConfig struct {
 	Caches IndexCacheConfig
+	// Metrics is the optional registry of the index metrics
+	Metrics metrics.Registry
 }
 
 // DefaultConfig returns default index config
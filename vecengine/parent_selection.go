@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package vecengine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+)
+
+// ErrInvalidMaxParents is returned by ChooseParents if maxParents doesn't allow even the self-parent.
+var ErrInvalidMaxParents = errors.New("max parents must be at least 1")
+
+// ChooseParents chooses the parents of a new event on top of the self-parent, among the heads.
+// The first parent is the self-parent, followed by at most maxParents-1 heads. The heads are chosen greedily,
+// each one is the head which advances the most the forkless causing of the roots by the new event,
+// measured by ForklessCauseProgress and capped by the quorum for every root. The ties are resolved in the
+// order of the heads, so the heads which don't advance the progress are chosen in that order.
+// The roots are usually the roots of the highest frame of the self-parent and the heads, so that the new event
+// becomes a root of the next frame as soon as possible.
+//
+// The heads of the validators observed as cheaters by the self-parent are excluded,
+// as their events don't count in the forkless cause. Among the forks of a validator at most one head is chosen,
+// and no head of the self-parent's creator is chosen. ErrInvalidMaxParents is returned if maxParents < 1.
+func (vi *Engine) ChooseParents(selfParent consensus.EventHash, heads, roots consensus.EventHashes, maxParents int) (consensus.EventHashes, error) {
+	if maxParents < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxParents, maxParents)
+	}
+	if err := vi.Err(); err != nil {
+		return nil, err
+	}
+	vi.InitBranchesInfo()
+
+	self := vi.getEvent(selfParent)
+	if self == nil || vi.GetHighestBefore(selfParent) == nil {
+		return nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Self-parent=%s not found", selfParent.String())
+	}
+	selfBefore := vi.GetMergedHighestBefore(selfParent)
+
+	chosen := consensus.EventHashes{selfParent}
+	chosenCreators := map[consensus.ValidatorID]bool{self.Creator(): true}
+	var (
+		candidates        consensus.EventHashes
+		candidateCreators []consensus.ValidatorID
+	)
+	for _, head := range heads {
+		e := vi.getEvent(head)
+		if e == nil {
+			return nil, consensus.IntegrityErrorf(consensus.ErrMissingEvent, "Head=%s not found", head.String())
+		}
+		if chosenCreators[e.Creator()] || selfBefore.IsForkDetected(vi.validatorIdxs[e.Creator()]) {
+			continue
+		}
+		candidates = append(candidates, head)
+		candidateCreators = append(candidateCreators, e.Creator())
+	}
+
+	quorum := vi.validators.Quorum()
+	for len(chosen) < maxParents && len(candidates) != 0 {
+		progress := make([]uint64, len(candidates))
+		for _, root := range roots {
+			_, candidatesProgress, err := vi.ForklessCauseProgress(selfParent, root, candidates, chosen[1:])
+			if err != nil {
+				return nil, err
+			}
+			for i, counter := range candidatesProgress {
+				progress[i] += uint64(min(counter.Sum(), quorum))
+			}
+		}
+		best := 0
+		for i := range candidates {
+			if progress[i] > progress[best] {
+				best = i
+			}
+		}
+		chosen = append(chosen, candidates[best])
+
+		// the other forks of the chosen head's creator aren't candidates anymore
+		creator := candidateCreators[best]
+		remaining, remainingCreators := candidates[:0], candidateCreators[:0]
+		for i, candidate := range candidates {
+			if candidateCreators[i] != creator {
+				remaining = append(remaining, candidate)
+				remainingCreators = append(remainingCreators, candidateCreators[i])
+			}
+		}
+		candidates, candidateCreators = remaining, remainingCreators
+	}
+	return chosen, vi.Err()
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package vecengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xsoniclabs/consensus/consensus"
+	"github.com/0xsoniclabs/consensus/consensus/consensustest"
+	"github.com/0xsoniclabs/kvdb/flushable"
+	"github.com/0xsoniclabs/kvdb/memorydb"
+)
+
+func TestChooseParents(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes, _, named := consensustest.ASCIIschemeToDAG(`
+a1_1  b1_1  c1_1  d1_1
+║     ║     ║     ║
+║     b2_2 ─╣     ║
+║     ║     ║     ║
+║     ║     c2_2 ─╣
+║     ║     ║     ║
+`)
+	vi := newTestIndex(consensus.EqualWeightValidators(nodes, 1), named, "a1_1", "b1_1", "c1_1", "d1_1", "b2_2", "c2_2")
+	ids := func(names ...string) consensus.EventHashes {
+		res := consensus.EventHashes{}
+		for _, name := range names {
+			res = append(res, named[name].ID())
+		}
+		return res
+	}
+	roots := ids("a1_1", "b1_1", "c1_1", "d1_1")
+
+	for _, test := range []struct {
+		heads      []string
+		maxParents int
+		expected   []string
+	}{
+		{heads: []string{"d1_1", "b2_2", "c2_2"}, maxParents: 1, expected: []string{"a1_1"}},
+		// b2_2 advances forkless causing of b1_1 and c1_1, while d1_1 advances only d1_1
+		{heads: []string{"d1_1", "b2_2"}, maxParents: 2, expected: []string{"a1_1", "b2_2"}},
+		// b2_2 and c2_2 advance equally, so the order of the heads decides, then c2_2 adds d1_1 on top of b2_2
+		{heads: []string{"d1_1", "b2_2", "c2_2"}, maxParents: 3, expected: []string{"a1_1", "b2_2", "c2_2"}},
+		{heads: []string{"d1_1", "c2_2", "b2_2"}, maxParents: 3, expected: []string{"a1_1", "c2_2", "b2_2"}},
+		{heads: []string{"d1_1", "b2_2", "c2_2"}, maxParents: 10, expected: []string{"a1_1", "b2_2", "c2_2", "d1_1"}},
+		// own events and the older events of the same validators aren't chosen
+		{heads: []string{"a1_1", "b1_1", "b2_2"}, maxParents: 10, expected: []string{"a1_1", "b2_2"}},
+		{heads: nil, maxParents: 10, expected: []string{"a1_1"}},
+	} {
+		parents, err := vi.ChooseParents(named["a1_1"].ID(), ids(test.heads...), roots, test.maxParents)
+		assertar.NoError(err)
+		assertar.Equal(ids(test.expected...), parents, "heads %v, max %d", test.heads, test.maxParents)
+	}
+
+	_, err := vi.ChooseParents(consensus.EventHash{1}, ids("b2_2"), roots, 2)
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+	_, err = vi.ChooseParents(named["a1_1"].ID(), consensus.EventHashes{{1}}, roots, 2)
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+	_, err = vi.ChooseParents(named["a1_1"].ID(), ids("b2_2"), consensus.EventHashes{{1}}, 2)
+	assertar.ErrorIs(err, consensus.ErrMissingEvent)
+
+	// the self-parent is always chosen, so maxParents must be at least 1
+	for _, maxParents := range []int{0, -1} {
+		_, err = vi.ChooseParents(named["a1_1"].ID(), ids("b2_2"), roots, maxParents)
+		assertar.ErrorIs(err, ErrInvalidMaxParents)
+		assertar.NotErrorIs(err, consensus.ErrIntegrity)
+	}
+}
+
+func TestChooseParents_Forks(t *testing.T) {
+	assertar := assert.New(t)
+
+	nodes := consensustest.GenNodes(4)
+	named := map[string]consensus.Event{}
+	add := func(name string, creator consensus.ValidatorID, parents ...string) {
+		e := &consensustest.TestEvent{}
+		e.SetCreator(creator)
+		e.SetSeq(1)
+		e.SetLamport(1)
+		e.SetParents(consensus.EventHashes{})
+		for i, p := range parents {
+			parent := named[p]
+			if i == 0 {
+				e.SetSeq(parent.Seq() + 1)
+			}
+			e.AddParent(parent.ID())
+			e.SetLamport(max(e.Lamport(), parent.Lamport()+1))
+		}
+		e.Name = name
+		e.SetID(consensustest.CalcHashForTestEvent(e))
+		named[name] = e
+	}
+	// d1 and d1x are forks of d, observed by b2 and so by c2
+	add("a1", nodes[0])
+	add("b1", nodes[1])
+	add("c1", nodes[2])
+	add("d1", nodes[3])
+	add("d1x", nodes[3])
+	add("b2", nodes[1], "b1", "d1", "d1x")
+	add("c2", nodes[2], "c1", "b2")
+	vi := newTestIndex(consensus.EqualWeightValidators(nodes, 1), named, "a1", "b1", "c1", "d1", "d1x", "b2", "c2")
+	roots := consensus.EventHashes{named["a1"].ID(), named["b1"].ID(), named["c1"].ID(), named["d1"].ID(), named["d1x"].ID()}
+
+	// the cheater observed by the self-parent isn't chosen
+	parents, err := vi.ChooseParents(named["c2"].ID(), consensus.EventHashes{named["d1"].ID(), named["a1"].ID()}, roots, 10)
+	assertar.NoError(err)
+	assertar.Equal(consensus.EventHashes{named["c2"].ID(), named["a1"].ID()}, parents)
+
+	// only one of the forks is chosen if the self-parent doesn't observe the cheater
+	parents, err = vi.ChooseParents(named["a1"].ID(), consensus.EventHashes{named["d1"].ID(), named["d1x"].ID(), named["b1"].ID()}, roots, 10)
+	assertar.NoError(err)
+	assertar.Len(parents, 3)
+	assertar.Contains(parents, named["b1"].ID())
+	assertar.NotEqual(consensus.NewEventsSet(parents...).Contains(named["d1"].ID()), consensus.NewEventsSet(parents...).Contains(named["d1x"].ID()))
+}
+
+// newTestIndex indexes the named events in the order
+func newTestIndex(validators *consensus.Validators, named map[string]consensus.Event, order ...string) *Engine {
+	events := make(map[consensus.EventHash]consensus.Event)
+	getEvent := func(id consensus.EventHash) consensus.Event {
+		return events[id]
+	}
+	vi := NewIndex(tCrit, LiteConfig(), GetEngineCallbacks)
+	vi.Reset(validators, flushable.Wrap(memorydb.New()), getEvent)
+	for _, name := range order {
+		e := named[name]
+		events[e.ID()] = e
+		if err := vi.Add(e); err != nil {
+			panic(err)
+		}
+		if err := vi.Flush(); err != nil {
+			panic(err)
+		}
+	}
+	return vi
+}
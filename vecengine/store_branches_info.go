@@ -11,8 +11,6 @@
 package vecengine
 
 import (
-	"errors"
-
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/0xsoniclabs/consensus/consensus"
@@ -22,18 +20,20 @@ import (
 func (vi *Engine) setRlp(table kvdb.Store, key []byte, val interface{}) {
 	buf, err := rlp.EncodeToBytes(val)
 	if err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "encoding %T: %w", val, err))
+		return
 	}
 
 	if err := table.Put(key, buf); err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err))
 	}
 }
 
 func (vi *Engine) getRlp(table kvdb.Store, key []byte, to interface{}) interface{} {
 	buf, err := table.Get(key)
 	if err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err))
+		return nil
 	}
 	if buf == nil {
 		return nil
@@ -41,7 +41,8 @@ func (vi *Engine) getRlp(table kvdb.Store, key []byte, to interface{}) interface
 
 	err = rlp.DecodeBytes(buf, to)
 	if err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrCorruptedRecord, "decoding %T: %w", to, err))
+		return nil
 	}
 	return to
 }
@@ -69,12 +70,11 @@ func (vi *Engine) SetEventBranchID(id consensus.EventHash, branchID consensus.Va
 }
 
 // GetEventBranchID reads the event's global branch ID
-func (vi *Engine) GetEventBranchID(id consensus.EventHash) consensus.ValidatorIndex {
+func (vi *Engine) GetEventBranchID(id consensus.EventHash) (consensus.ValidatorIndex, error) {
 	b := vi.getBytes(vi.table.EventBranch, id)
 	if b == nil {
-		vi.crit(errors.New("failed to read event's branch ID (inconsistent DB)"))
-		return 0
+		return 0, consensus.IntegrityErrorf(consensus.ErrInconsistentDB, "failed to read event's branch ID, event=%s", id.String())
 	}
 	branchID := consensus.BytesToValidator(b)
-	return branchID
+	return branchID, nil
 }
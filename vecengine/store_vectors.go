@@ -19,7 +19,7 @@ func (vi *Engine) getBytes(table kvdb.Store, id consensus.EventHash) []byte {
 	key := id.Bytes()
 	b, err := table.Get(key)
 	if err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err))
 	}
 	return b
 }
@@ -28,13 +28,16 @@ func (vi *Engine) setBytes(table kvdb.Store, id consensus.EventHash, b []byte) {
 	key := id.Bytes()
 	err := table.Put(key, b)
 	if err != nil {
-		vi.crit(err)
+		vi.fail(consensus.IntegrityErrorf(consensus.ErrStorageIO, "%w", err))
 	}
 }
 
 // GetLowestAfter reads the vector from DB
 func (vi *Engine) GetLowestAfter(id consensus.EventHash) *LowestAfterSeq {
-	if bVal, okGet := vi.cache.LowestAfterSeq.Get(id); okGet {
+	vi.mu.Lock()
+	bVal, okGet := vi.cache.LowestAfterSeq.Get(id)
+	vi.mu.Unlock()
+	if okGet {
 		return bVal.(*LowestAfterSeq)
 	}
 
@@ -42,13 +45,18 @@ func (vi *Engine) GetLowestAfter(id consensus.EventHash) *LowestAfterSeq {
 	if b == nil {
 		return nil
 	}
+	vi.mu.Lock()
 	vi.cache.LowestAfterSeq.Add(id, &b, uint(len(b)))
+	vi.mu.Unlock()
 	return &b
 }
 
 // GetHighestBefore reads the vector from DB
 func (vi *Engine) GetHighestBefore(id consensus.EventHash) *HighestBeforeSeq {
-	if bVal, okGet := vi.cache.HighestBeforeSeq.Get(id); okGet {
+	vi.mu.Lock()
+	bVal, okGet := vi.cache.HighestBeforeSeq.Get(id)
+	vi.mu.Unlock()
+	if okGet {
 		return bVal.(*HighestBeforeSeq)
 	}
 
@@ -56,7 +64,9 @@ func (vi *Engine) GetHighestBefore(id consensus.EventHash) *HighestBeforeSeq {
 	if b == nil {
 		return nil
 	}
+	vi.mu.Lock()
 	vi.cache.HighestBeforeSeq.Add(id, &b, uint(len(b)))
+	vi.mu.Unlock()
 	return &b
 }
 
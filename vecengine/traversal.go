@@ -11,8 +11,6 @@
 package vecengine
 
 import (
-	"errors"
-
 	"github.com/0xsoniclabs/consensus/consensus"
 )
 
@@ -35,7 +33,7 @@ func (vi *Engine) DfsSubgraph(head consensus.Event, walk func(consensus.EventHas
 
 		event := vi.getEvent(curr)
 		if event == nil {
-			return errors.New("event not found " + curr.String())
+			return consensus.IntegrityErrorf(consensus.ErrMissingEvent, "event not found %s", curr.String())
 		}
 
 		// memorize parents